@@ -4,14 +4,14 @@ go 1.17
 
 require (
 	github.com/aws/aws-sdk-go v1.44.4
+	github.com/jmespath/go-jmespath v0.4.0
 	github.com/johannesboyne/gofakes3 v0.0.0-20220413173033-532d036b4e0d
 	github.com/spf13/cobra v1.4.0
-
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/inconshreveable/mousetrap v1.0.0 // indirect
-	github.com/jmespath/go-jmespath v0.4.0 // indirect
 	github.com/ryszard/goskiplist v0.0.0-20150312221310-2dfbae5fcf46 // indirect
 	github.com/shabbyrobe/gocovmerge v0.0.0-20190829150210-3e036491d500 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
@@ -2,11 +2,9 @@ package main
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"mime"
-	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,148 +14,162 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/corehandlers"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/endpoints"
-	"github.com/aws/aws-sdk-go/aws/request"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/shvc/s3cli/pkg/s3cli"
 	"github.com/spf13/cobra"
 )
 
-const (
-	defaultDialTimeout           = 10
-	defaultResponseHeaderTimeout = 20
-)
-
 var (
 	// version to record s3cli version
 	version = "1.2.3"
-	// endpoint ENV Var
-	endpointEnvVar = "S3_ENDPOINT"
-	// With ForcePathStyle(pathStyle=true):
-	// 	https://s3.us-west-2.amazonaws.com/BUCKET/KEY
-	// Without ForcePathStyle(pathStyle=false):
-	// 	https://BUCKET.s3.us-west-2.amazonaws.com/KEY
-	pathStyle                 = true
-	dialTimeout               int
-	responseHeaderTimeout     int
-	httpKeepAlive             = true
-	v2Sign                    = false
-	disableContentMd5Validate = false
-	noProxy                   = false
 )
 
-func newS3Client(sc *S3Cli) (*s3.S3, error) {
-	if sc.endpoint == "" {
-		sc.endpoint = os.Getenv(endpointEnvVar)
-	}
-	if sc.endpoint == "" {
-		return nil, errors.New("unknown endpoint")
-	}
+// serviceRoles is the description of --src-* and --dst-* flags
+var serviceRoles = map[string]string{"src": "source", "dst": "destination"}
 
-	if !strings.HasPrefix(sc.endpoint, "http://") && !strings.HasPrefix(sc.endpoint, "https://") {
-		sc.endpoint = "http://" + sc.endpoint
+// addSourceFlags add flags of a source and a destination S3 service(different endpoint) to cmd
+func addSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().String("src-alias", "", "source alias in config file, default the alias of source alias:bucket/key")
+	cmd.Flags().String("dst-alias", "", "destination alias in config file")
+	for _, role := range []string{"src", "dst"} {
+		desc := serviceRoles[role]
+		cmd.Flags().String(role+"-endpoint", "", desc+" S3 endpoint(http://host:port), default same as --endpoint")
+		cmd.Flags().String(role+"-profile", "", desc+" profile in credentials file")
+		cmd.Flags().String(role+"-ak", "", desc+" S3 Access Key")
+		cmd.Flags().String(role+"-sk", "", desc+" S3 Secret Key")
+		cmd.Flags().String(role+"-tk", "", desc+" S3 session token")
+		cmd.Flags().String(role+"-region", "", desc+" S3 region, default same as --region")
+		cmd.Flags().Bool(role+"-v2sign", false, desc+" S3 signature v2, default same as --v2sign")
+		cmd.Flags().Bool(role+"-path-style", true, desc+" use path style, default same as --path-style")
 	}
+}
 
-	if sc.accessKey == "" {
-		sc.accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
-		if sc.accessKey == "" {
-			sc.accessKey = os.Getenv("AWS_ACCESS_KEY")
-		}
-	}
+// addLockFlags add Object Lock flags of a new Object to cmd
+func addLockFlags(cmd *cobra.Command) {
+	cmd.Flags().String("lock-mode", "", "Object Lock retention mode(GOVERNANCE or COMPLIANCE), requires --lock-retain-until")
+	cmd.Flags().String("lock-retain-until", "", "Object Lock retain until date(2006-01-02, RFC3339 or days from now like 30d)")
+	cmd.Flags().Bool("legal-hold", false, "place a legal hold on the Object")
+}
 
-	if sc.secretKey == "" {
-		sc.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
-		if sc.secretKey == "" {
-			sc.secretKey = os.Getenv("AWS_SECRET_KEY")
+// objectOptions create the ObjectOptions of new Object flags(--tag, --tagging-directive, --website-redirect and lock flags) of cmd
+func objectOptions(cmd *cobra.Command) (s3cli.ObjectOptions, error) {
+	opt := s3cli.ObjectOptions{}
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	tagging, err := s3cli.ParseTagging(tags)
+	if err != nil {
+		return opt, err
+	}
+	opt.Tagging = tagging
+	opt.TaggingDirective, _ = cmd.Flags().GetString("tagging-directive")
+	opt.LockMode, _ = cmd.Flags().GetString("lock-mode")
+	if until, _ := cmd.Flags().GetString("lock-retain-until"); until != "" {
+		if opt.LockRetainUntil, err = s3cli.ParseRetainUntil(until); err != nil {
+			return opt, err
 		}
 	}
+	opt.LegalHold, _ = cmd.Flags().GetBool("legal-hold")
+	opt.WebsiteRedirect, _ = cmd.Flags().GetString("website-redirect")
+	return opt, nil
+}
 
-	if sc.accessKey == "" && sc.secretKey != "" {
-		return nil, errors.New("unknown accessKey")
-	}
+// newSourceCli create the s3cli.S3Cli of source S3 service specified by --src-* flags or
+// source alias(--src-alias or argAlias), unspecified settings are inherited from sc,
+// returns nil if neither --src-endpoint nor source alias is set
+func newSourceCli(cmd *cobra.Command, sc *s3cli.S3Cli, cfg *s3cli.Config, argAlias string) (*s3cli.S3Cli, error) {
+	return newServiceCli(cmd, "src", sc, cfg, argAlias)
+}
 
-	if sc.accessKey != "" && sc.secretKey == "" {
-		return nil, errors.New("unknown secretKey")
+// newServiceCli create the s3cli.S3Cli of the role(src or dst) S3 service specified by --<role>-* flags or
+// alias(--<role>-alias or argAlias), unspecified settings are inherited from sc,
+// returns nil if neither --<role>-endpoint nor alias is set
+func newServiceCli(cmd *cobra.Command, role string, sc *s3cli.S3Cli, cfg *s3cli.Config, argAlias string) (*s3cli.S3Cli, error) {
+	desc := serviceRoles[role]
+	endpoint, _ := cmd.Flags().GetString(role + "-endpoint")
+	alias, _ := cmd.Flags().GetString(role + "-alias")
+	if alias == "" {
+		alias = argAlias
 	}
-
-	if sc.tokenKey == "" {
-		sc.tokenKey = os.Getenv("AWS_SESSION_TOKEN")
+	if endpoint == "" && alias == "" {
+		return nil, nil
 	}
-
-	tp := &http.Transport{
-		TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-		Dial:                  (&net.Dialer{Timeout: time.Duration(dialTimeout) * time.Second}).Dial,
-		ResponseHeaderTimeout: time.Duration(responseHeaderTimeout) * time.Second,
-		DisableKeepAlives:     !httpKeepAlive,
+	svc := &s3cli.S3Cli{
+		Profile:               sc.Profile,
+		Endpoint:              sc.Endpoint,
+		AccessKey:             sc.AccessKey,
+		SecretKey:             sc.SecretKey,
+		SessionToken:          sc.SessionToken,
+		Region:                sc.Region,
+		PathStyle:             sc.PathStyle,
+		V2Sign:                sc.V2Sign,
+		Proxy:                 sc.Proxy,
+		NoProxy:               sc.NoProxy,
+		DialTimeout:           sc.DialTimeout,
+		ResponseHeaderTimeout: sc.ResponseHeaderTimeout,
+		DisableKeepAlives:     sc.DisableKeepAlives,
+		MaxRetries:            sc.MaxRetries,
+		RetryMinDelay:         sc.RetryMinDelay,
+		RetryMaxDelay:         sc.RetryMaxDelay,
+		Insecure:              sc.Insecure,
+		CABundle:              sc.CABundle,
+		ClientCert:            sc.ClientCert,
+		ClientKey:             sc.ClientKey,
+		TLSServerName:         sc.TLSServerName,
+		TLSMinVersion:         sc.TLSMinVersion,
+		PresignExp:            sc.PresignExp,
+		Output:                sc.Output,
+		Writer:                sc.Writer,
+		Debug:                 sc.Debug,
+		Trace:                 sc.Trace,
 	}
-
-	if !noProxy {
-		tp.Proxy = http.ProxyFromEnvironment
+	if alias != "" {
+		a, ok := cfg.Aliases[alias]
+		if !ok {
+			return nil, fmt.Errorf("%s alias %s not found", desc, alias)
+		}
+		s3cli.ApplyAlias(svc, a, func(name string) bool {
+			return cmd.Flags().Changed(role + "-" + name)
+		})
 	}
-
-	cfg := &aws.Config{
-		Region:                        aws.String(sc.region),
-		MaxRetries:                    aws.Int(0),
-		S3ForcePathStyle:              aws.Bool(pathStyle),
-		S3DisableContentMD5Validation: aws.Bool(disableContentMd5Validate),
-		HTTPClient: &http.Client{
-			Transport: tp,
-		},
-		EndpointResolver: endpoints.ResolverFunc(
-			func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
-				return endpoints.ResolvedEndpoint{
-					URL:           sc.endpoint,
-					SigningRegion: sc.region,
-					SigningName:   service,
-					SigningMethod: "v4",
-				}, nil
-
-			}),
+	if endpoint != "" {
+		svc.Endpoint = endpoint
 	}
-
-	if sc.profile != "" {
-		cfg.Credentials = credentials.NewSharedCredentials("", sc.profile)
-		cfg.Credentials.Get()
-	} else if sc.accessKey == "" && sc.secretKey == "" {
-		cfg.Credentials = credentials.AnonymousCredentials
-	} else {
-		credentials.NewEnvCredentials()
-		cfg.Credentials = credentials.NewStaticCredentials(sc.accessKey, sc.secretKey, sc.tokenKey)
+	if cmd.Flags().Changed(role+"-profile") || cmd.Flags().Changed(role+"-ak") || cmd.Flags().Changed(role+"-sk") {
+		svc.Profile, _ = cmd.Flags().GetString(role + "-profile")
+		svc.AccessKey, _ = cmd.Flags().GetString(role + "-ak")
+		svc.SecretKey, _ = cmd.Flags().GetString(role + "-sk")
+		svc.SessionToken, _ = cmd.Flags().GetString(role + "-tk")
 	}
-	sess := session.Must(session.NewSession(cfg))
-
-	if sc.debug {
-		sess.Config.LogLevel = aws.LogLevel(aws.LogDebug)
+	if v, _ := cmd.Flags().GetString(role + "-region"); v != "" {
+		svc.Region = v
 	}
-	svc := s3.New(sess)
-	if v2Sign {
-		cred, _ := cfg.Credentials.Get()
-		svc.Handlers.Sign.Clear()
-		// auto fill content-length header
-		svc.Handlers.Sign.PushBackNamed(corehandlers.BuildContentLengthHandler)
-		svc.Handlers.Sign.PushBack(func(req *request.Request) {
-			if req.Config.Credentials == credentials.AnonymousCredentials {
-				return
-			}
-			if req.ExpireTime > 0 {
-				v2Presign(cred.AccessKeyID, cred.SecretAccessKey, req.ExpireTime, req.HTTPRequest)
-			} else {
-				sign(cred.AccessKeyID, cred.SecretAccessKey, req.HTTPRequest)
-			}
-		})
+	if cmd.Flags().Changed(role + "-v2sign") {
+		svc.V2Sign, _ = cmd.Flags().GetBool(role + "-v2sign")
 	}
-
+	if cmd.Flags().Changed(role + "-path-style") {
+		svc.PathStyle, _ = cmd.Flags().GetBool(role + "-path-style")
+	}
+	client, err := s3cli.NewS3Client(svc)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", desc, err)
+	}
+	svc.Client = client
 	return svc, nil
 }
 
 func main() {
-	sc := S3Cli{}
+	sc := s3cli.S3Cli{}
 	objectMetadata := []string{}
 	objectContentType := ""
 	objectContentData := ""
+	configFile := ""
+	aliasName := ""
+	filter := ""
+	httpKeepAlive := true
+	outputTmpl := ""
+	srcAliasName := ""
+	aliasArgs := map[int]bool{}
+	var cliCfg *s3cli.Config
 	ctx, cancelCtx := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
 	defer cancelCtx()
 	var rootCmd = &cobra.Command{
@@ -173,37 +185,98 @@ EnvVar:
 	AWS_SECRET_ACCESS_KEY=sk     (only read if flag --sk and --profile not set)
 	AWS_SECRET_KEY=sk            (only read if AWS_SECRET_ACCESS_KEY is not set)
 	AWS_SESSION_TOKEN=token      (only read if --tk is not set)
+	S3CLI_CONFIG=file            (only read if flag --config is not set)
+	S3_INSECURE=true             (only read if flag --insecure is not set)
+	AWS_CA_BUNDLE=file           (only read if flag --ca-bundle is not set)
+	S3_CLIENT_CERT=file          (only read if flag --client-cert is not set)
+	S3_CLIENT_KEY=file           (only read if flag --client-key is not set)
+	S3_TLS_SERVER_NAME=name      (only read if flag --tls-server-name is not set)
+	S3_TLS_MIN_VERSION=1.2       (only read if flag --tls-min-version is not set)
+Alias:
+	s3cli alias set minio -e http://host:9000 --ak ak --sk sk
+	s3cli ls minio:bucket-name
+	s3cli ls bucket-name --alias minio
 	`,
 		Version: version,
 		Hidden:  true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			client, err := newS3Client(&sc)
+			if err := sc.SetOutput(filter, outputTmpl, cmd.Flags().Changed("output")); err != nil {
+				return err
+			}
+			cfg, err := s3cli.LoadConfig(s3cli.ConfigFilePath(configFile))
 			if err != nil {
-				return sc.errorHandler(err)
+				return err
+			}
+			cliCfg = cfg
+			// alias:bucket/key arguments, the last alias is the S3 service of
+			// command, a different first alias is the source of copy/sync
+			argAliases := []string{}
+			for i, arg := range args {
+				if name, rest := cfg.SplitAlias(arg); name != "" {
+					args[i] = rest
+					aliasArgs[i] = true
+					argAliases = append(argAliases, name)
+				}
+			}
+			name := aliasName
+			if name == "" && len(argAliases) > 0 {
+				name = argAliases[len(argAliases)-1]
+			}
+			if len(argAliases) > 0 && argAliases[0] != name {
+				srcAliasName = argAliases[0]
+			}
+			if name != "" {
+				a, ok := cfg.Aliases[name]
+				if !ok {
+					return fmt.Errorf("alias %s not found in %s", name, s3cli.ConfigFilePath(configFile))
+				}
+				s3cli.ApplyAlias(&sc, a, cmd.Flags().Changed)
+			}
+
+			sc.DisableKeepAlives = !httpKeepAlive
+			client, err := s3cli.NewS3Client(&sc)
+			if err != nil {
+				return sc.ErrorHandler(err)
 			}
 			sc.Client = client
 			return nil
 		},
 	}
-	rootCmd.PersistentFlags().BoolVarP(&sc.debug, "debug", "", false, "show SDK debug log")
-	rootCmd.PersistentFlags().StringVarP(&sc.output, "output", "o", outputSimple, "output format(verbose,simple,json,line)")
-	rootCmd.PersistentFlags().BoolVarP(&sc.presign, "presign", "", false, "presign Request and exit")
-	rootCmd.PersistentFlags().DurationVarP(&sc.presignExp, "presign-exp", "", 24*time.Hour, "presign Request expiration duration")
-	rootCmd.PersistentFlags().StringVarP(&sc.endpoint, "endpoint", "e", "", "S3 endpoint(http://host:port)")
-	rootCmd.PersistentFlags().StringVarP(&sc.profile, "profile", "p", "", "profile in credentials file")
-	rootCmd.PersistentFlags().StringVarP(&sc.region, "region", "R", s3.BucketLocationConstraintCnNorth1, "S3 region")
-	rootCmd.PersistentFlags().StringVarP(&sc.accessKey, "ak", "a", "", "S3 Access Key(only read if profile not set)")
-	rootCmd.PersistentFlags().StringVarP(&sc.secretKey, "sk", "s", "", "S3 Secret Key(only read if profile not set)")
-	rootCmd.PersistentFlags().StringVarP(&sc.tokenKey, "tk", "", "", "S3 session token")
-	rootCmd.PersistentFlags().BoolVarP(&pathStyle, "path-style", "", true, "use path style")
+	rootCmd.PersistentFlags().BoolVarP(&sc.Debug, "debug", "", false, "show SDK debug log")
+	rootCmd.PersistentFlags().BoolVarP(&sc.Trace, "trace", "", false, "print DNS, connect, TLS, TTFB and transfer timings and request IDs of every http request to stderr")
+	rootCmd.PersistentFlags().StringVarP(&sc.Output, "output", "o", s3cli.OutputSimple, "output format(verbose,simple,json,line,table,csv,yaml,ndjson,template)")
+	rootCmd.PersistentFlags().StringVarP(&outputTmpl, "template", "", "", "Go template of each output record(implies -o template), funcs: human, rfc3339, unix, timefmt, urlescape, queryescape, trimquote")
+	rootCmd.PersistentFlags().StringVarP(&filter, "filter", "", "", "JMESPath expression applied to JSON output(implies -o json)")
+	rootCmd.PersistentFlags().StringVarP(&filter, "jq", "", "", "alias of --filter")
+	rootCmd.PersistentFlags().BoolVarP(&sc.Presign, "presign", "", false, "presign Request and exit")
+	rootCmd.PersistentFlags().DurationVarP(&sc.PresignExp, "presign-exp", "", 24*time.Hour, "presign Request expiration duration")
+	rootCmd.PersistentFlags().StringVarP(&sc.Endpoint, "endpoint", "e", "", "S3 endpoint(http://host:port)")
+	rootCmd.PersistentFlags().StringVarP(&sc.Profile, "profile", "p", "", "profile in credentials file")
+	rootCmd.PersistentFlags().StringVarP(&sc.Region, "region", "R", s3.BucketLocationConstraintCnNorth1, "S3 region")
+	rootCmd.PersistentFlags().StringVarP(&sc.AccessKey, "ak", "a", "", "S3 Access Key(only read if profile not set)")
+	rootCmd.PersistentFlags().StringVarP(&sc.SecretKey, "sk", "s", "", "S3 Secret Key(only read if profile not set)")
+	rootCmd.PersistentFlags().StringVarP(&sc.SessionToken, "tk", "", "", "S3 session token")
+	rootCmd.PersistentFlags().BoolVarP(&sc.PathStyle, "path-style", "", true, "use path style")
 	rootCmd.PersistentFlags().BoolVarP(&httpKeepAlive, "http-keep-alive", "", true, "http Keep-Alive")
-	rootCmd.PersistentFlags().BoolVarP(&v2Sign, "v2sign", "", false, "S3 signature v2")
-	rootCmd.PersistentFlags().BoolVarP(&noProxy, "noproxy", "", false, "S3 http client not use proxy")
-	rootCmd.PersistentFlags().BoolVarP(&disableContentMd5Validate, "no-md5-validate", "", false, "disable content md5 validate(header Content-Md5)")
-	rootCmd.PersistentFlags().IntVarP(&dialTimeout, "dial-timeout", "", defaultDialTimeout, "http dial timeout in seconds")
-	rootCmd.PersistentFlags().IntVarP(&responseHeaderTimeout, "response-header-timeout", "", defaultResponseHeaderTimeout, "http response header timeout in seconds")
-	rootCmd.PersistentFlags().StringArrayVarP(&sc.header, "header", "H", nil, "Pass custom header(s) to server(format Key:Value)")
-	rootCmd.PersistentFlags().StringArrayVarP(&sc.query, "query", "Q", nil, "Pass custom query parameter(s) to server(format Key=Value)")
+	rootCmd.PersistentFlags().BoolVarP(&sc.V2Sign, "v2sign", "", false, "S3 signature v2")
+	rootCmd.PersistentFlags().BoolVarP(&sc.NoProxy, "noproxy", "", false, "S3 http client not use proxy")
+	rootCmd.PersistentFlags().BoolVarP(&sc.DisableContentMD5Validate, "no-md5-validate", "", false, "disable content md5 validate(header Content-Md5)")
+	rootCmd.PersistentFlags().StringVarP(&sc.Proxy, "proxy", "", "", "S3 http client proxy URL(default read from env)")
+	rootCmd.PersistentFlags().IntVarP(&sc.DialTimeout, "dial-timeout", "", s3cli.DefaultDialTimeout, "http dial timeout in seconds")
+	rootCmd.PersistentFlags().IntVarP(&sc.ResponseHeaderTimeout, "response-header-timeout", "", s3cli.DefaultResponseHeaderTimeout, "http response header timeout in seconds")
+	rootCmd.PersistentFlags().IntVarP(&sc.MaxRetries, "max-retries", "", s3cli.DefaultMaxRetries, "max retries of throttled(SlowDown, 429, 503), 5xx and connection-failed requests")
+	rootCmd.PersistentFlags().DurationVarP(&sc.RetryMinDelay, "retry-min-delay", "", s3cli.DefaultRetryMinDelay, "min delay before retry(exponential backoff with jitter)")
+	rootCmd.PersistentFlags().DurationVarP(&sc.RetryMaxDelay, "retry-max-delay", "", s3cli.DefaultRetryMaxDelay, "max delay before retry(Retry-After header is honored up to it)")
+	rootCmd.PersistentFlags().BoolVarP(&sc.Insecure, "insecure", "k", false, "skip server TLS certificate verification")
+	rootCmd.PersistentFlags().StringVarP(&sc.CABundle, "ca-bundle", "", "", "PEM file of CA certificates to verify server certificate")
+	rootCmd.PersistentFlags().StringVarP(&sc.ClientCert, "client-cert", "", "", "PEM file of TLS client certificate")
+	rootCmd.PersistentFlags().StringVarP(&sc.ClientKey, "client-key", "", "", "PEM file of TLS client key")
+	rootCmd.PersistentFlags().StringVarP(&sc.TLSServerName, "tls-server-name", "", "", "server name to verify TLS certificate")
+	rootCmd.PersistentFlags().StringVarP(&sc.TLSMinVersion, "tls-min-version", "", "", "min TLS version(1.0, 1.1, 1.2 or 1.3)")
+	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "", "", "config file(default ~/.config/s3cli/config.yaml)")
+	rootCmd.PersistentFlags().StringVarP(&aliasName, "alias", "", "", "alias(endpoint and credentials) in config file")
+	rootCmd.PersistentFlags().StringArrayVarP(&sc.Header, "header", "H", nil, "Pass custom header(s) to server(format Key:Value)")
+	rootCmd.PersistentFlags().StringArrayVarP(&sc.Query, "query", "Q", nil, "Pass custom query parameter(s) to server(format Key=Value)")
 	// presign(V2) command
 	presignCmd := &cobra.Command{
 		Use:   "presign <bucket/key>",
@@ -223,13 +296,13 @@ EnvVar:
 			case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPost, http.MethodDelete:
 				break
 			default:
-				return sc.errorHandler(fmt.Errorf("invalid http method: %s", method))
+				return sc.ErrorHandler(fmt.Errorf("invalid http method: %s", method))
 			}
 			var s string
 			var err error
-			s, err = sc.presignV2Raw(method, args[0], objectContentType)
+			s, err = sc.PresignV2Raw(method, args[0], objectContentType)
 			if err != nil {
-				return sc.errorHandler(err)
+				return sc.ErrorHandler(err)
 			}
 			fmt.Println(s)
 			return nil
@@ -250,7 +323,7 @@ EnvVar:
 	s3cli create-bucket bkt1 bkt2 bkt3`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return sc.errorHandler(sc.bucketCreate(ctx, args))
+			return sc.ErrorHandler(sc.RenderBucketCreate(sc.BucketCreate(ctx, args)))
 		},
 	}
 	rootCmd.AddCommand(bucketCreateCmd)
@@ -265,7 +338,7 @@ EnvVar:
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return sc.errorHandler(sc.bucketEncryptionGet(ctx, args[0]))
+			return sc.ErrorHandler(sc.RenderBucketEncryption(sc.BucketEncryptionGet(ctx, args[0])))
 		},
 	}
 	rootCmd.AddCommand(bucketEncryptionGetCmd)
@@ -280,7 +353,7 @@ EnvVar:
 `,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return sc.errorHandler(sc.bucketEncryptionPut(ctx, args[0], args[1]))
+			return sc.ErrorHandler(sc.BucketEncryptionPut(ctx, args[0], args[1]))
 		},
 	}
 	rootCmd.AddCommand(bucketEncryptionPutCmd)
@@ -295,7 +368,7 @@ EnvVar:
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return sc.errorHandler(sc.bucketEncryptionDelete(ctx, args[0]))
+			return sc.ErrorHandler(sc.BucketEncryptionDelete(ctx, args[0]))
 		},
 	}
 	rootCmd.AddCommand(bucketEncryptionDeleteCmd)
@@ -311,9 +384,9 @@ EnvVar:
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 1 {
-				return sc.errorHandler(sc.bucketPolicyGet(ctx, args[0]))
+				return sc.ErrorHandler(sc.RenderBucketPolicy(sc.BucketPolicyGet(ctx, args[0])))
 			}
-			return sc.errorHandler(sc.bucketPolicySet(ctx, args[0], args[1]))
+			return sc.ErrorHandler(sc.BucketPolicySet(ctx, args[0], args[1]))
 		},
 	}
 	rootCmd.AddCommand(bucketPolicyCmd)
@@ -335,11 +408,11 @@ EnvVar:
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 1 {
-				bucket, prefix := sc.splitKeyValue(args[0], "/")
+				bucket, prefix := sc.SplitKeyValue(args[0], "/")
 				if prefix == "" {
-					return sc.errorHandler(sc.bucketVersioningGet(ctx, bucket))
+					return sc.ErrorHandler(sc.RenderBucketVersioning(sc.BucketVersioningGet(ctx, bucket)))
 				}
-				return sc.errorHandler(sc.listObjectVersions(ctx, bucket, prefix))
+				return sc.ErrorHandler(sc.RenderObjectVersions(sc.ListObjectVersions(ctx, bucket, prefix)))
 			}
 
 			var status string
@@ -349,9 +422,9 @@ EnvVar:
 			case strings.ToLower(s3.BucketVersioningStatusSuspended):
 				status = s3.BucketVersioningStatusSuspended
 			default:
-				return sc.errorHandler(fmt.Errorf("invalid versioning: %v", args[1]))
+				return sc.ErrorHandler(fmt.Errorf("invalid versioning: %v", args[1]))
 			}
-			return sc.errorHandler(sc.bucketVersioningSet(ctx, args[0], status))
+			return sc.ErrorHandler(sc.BucketVersioningSet(ctx, args[0], status))
 		},
 	}
 	rootCmd.AddCommand(bucketVersionCmd)
@@ -370,15 +443,15 @@ EnvVar:
 `,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, _ := sc.splitKeyValue(args[0], "/")
+			bucket, _ := sc.SplitKeyValue(args[0], "/")
 			if len(args) == 1 {
 				if corsDelete {
-					return sc.errorHandler(sc.deleteBucketCors(ctx, bucket))
+					return sc.ErrorHandler(sc.DeleteBucketCors(ctx, bucket))
 				} else {
-					return sc.errorHandler(sc.getBucketCors(ctx, bucket))
+					return sc.ErrorHandler(sc.RenderBucketCors(sc.GetBucketCors(ctx, bucket)))
 				}
 			} else {
-				return sc.errorHandler(sc.putBucketCors(ctx, bucket, args[1]))
+				return sc.ErrorHandler(sc.PutBucketCors(ctx, bucket, args[1]))
 			}
 
 		},
@@ -386,7 +459,266 @@ EnvVar:
 	bucketCorsCmd.Flags().BoolVar(&corsDelete, "delete", false, "delete bucket cors")
 	rootCmd.AddCommand(bucketCorsCmd)
 
+	websiteDelete := false
+	websiteOpt := s3cli.WebsiteOptions{}
+	bucketWebsiteCmd := &cobra.Command{
+		Use:   "website <bucket>",
+		Short: "bucket website",
+		Long: `get/put/delete bucket static website configuration usage:
+* get Bucket website configuration
+	s3cli website bucket-name
+* put(replace) Bucket website configuration
+	s3cli website bucket-name --index index.html --error 404.html
+	s3cli website bucket-name --index index.html --routing-rules rules.yaml
+* redirect all requests of Bucket website to another host
+	s3cli website bucket-name --redirect-all https://example.com
+* delete Bucket website configuration
+	s3cli website bucket-name --delete
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, _ := sc.SplitKeyValue(args[0], "/")
+			configFlags := false
+			for _, name := range []string{"index", "error", "redirect-all", "routing-rules"} {
+				configFlags = configFlags || cmd.Flags().Changed(name)
+			}
+			if websiteDelete {
+				if configFlags {
+					return sc.ErrorHandler(errors.New("--delete takes no website configuration"))
+				}
+				return sc.ErrorHandler(sc.BucketWebsiteDelete(ctx, bucket))
+			}
+			if configFlags {
+				cfg, err := websiteOpt.Config()
+				if err != nil {
+					return sc.ErrorHandler(err)
+				}
+				return sc.ErrorHandler(sc.BucketWebsitePut(ctx, bucket, cfg))
+			}
+			return sc.ErrorHandler(sc.RenderBucketWebsite(sc.BucketWebsiteGet(ctx, bucket)))
+		},
+	}
+	bucketWebsiteCmd.Flags().BoolVar(&websiteDelete, "delete", false, "delete bucket website configuration")
+	bucketWebsiteCmd.Flags().StringVar(&websiteOpt.IndexDocument, "index", "", "index document suffix(index.html)")
+	bucketWebsiteCmd.Flags().StringVar(&websiteOpt.ErrorDocument, "error", "", "error document key")
+	bucketWebsiteCmd.Flags().StringVar(&websiteOpt.RedirectAll, "redirect-all", "", "redirect all requests to host(or protocol://host)")
+	bucketWebsiteCmd.Flags().StringVar(&websiteOpt.RoutingRulesFile, "routing-rules", "", "routing rules(JSON or YAML list) file")
+	rootCmd.AddCommand(bucketWebsiteCmd)
+
+	loggingTarget, loggingPrefix := "", ""
+	loggingDisable := false
+	bucketLoggingCmd := &cobra.Command{
+		Use:   "logging <bucket>",
+		Short: "bucket access logging",
+		Long: `get/put bucket server access logging usage:
+* get Bucket access logging(nothing printed if disabled)
+	s3cli logging bucket-name
+* put Bucket access logging to target Bucket with prefix
+	s3cli logging bucket-name --target-bucket log-bucket --target-prefix bucket-name/
+* disable Bucket access logging
+	s3cli logging bucket-name --disable
+`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, _ := sc.SplitKeyValue(args[0], "/")
+			if loggingDisable {
+				if loggingTarget != "" || loggingPrefix != "" {
+					return sc.ErrorHandler(errors.New("--disable takes no target"))
+				}
+				return sc.ErrorHandler(sc.BucketLoggingPut(ctx, bucket, "", ""))
+			}
+			if loggingTarget != "" || loggingPrefix != "" {
+				return sc.ErrorHandler(sc.BucketLoggingPut(ctx, bucket, loggingTarget, loggingPrefix))
+			}
+			return sc.ErrorHandler(sc.RenderBucketLogging(sc.BucketLoggingGet(ctx, bucket)))
+		},
+	}
+	bucketLoggingCmd.Flags().StringVar(&loggingTarget, "target-bucket", "", "target Bucket of access logs")
+	bucketLoggingCmd.Flags().StringVar(&loggingPrefix, "target-prefix", "", "key prefix of access logs in target Bucket")
+	bucketLoggingCmd.Flags().BoolVar(&loggingDisable, "disable", false, "disable bucket access logging")
+	rootCmd.AddCommand(bucketLoggingCmd)
+
+	notificationDelete := false
+	bucketNotificationCmd := &cobra.Command{
+		Use:   "notification <bucket> [config-file]",
+		Short: "bucket notification",
+		Long: `get/put/delete bucket event notification configuration usage:
+* get Bucket notification configuration
+	s3cli notification bucket-name
+* put(replace) Bucket queue/topic/lambda notifications of a JSON/YAML file(validated before put)
+	s3cli notification bucket-name notification.json
+* delete(put an empty configuration) Bucket notifications
+	s3cli notification bucket-name --delete
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, _ := sc.SplitKeyValue(args[0], "/")
+			if notificationDelete {
+				if len(args) > 1 {
+					return sc.ErrorHandler(errors.New("--delete takes no config file"))
+				}
+				return sc.ErrorHandler(sc.BucketNotificationPut(ctx, bucket, &s3.NotificationConfiguration{}))
+			}
+			if len(args) > 1 {
+				cfg, err := s3cli.LoadNotificationConfig(args[1])
+				if err != nil {
+					return sc.ErrorHandler(err)
+				}
+				return sc.ErrorHandler(sc.BucketNotificationPut(ctx, bucket, cfg))
+			}
+			return sc.ErrorHandler(sc.RenderBucketNotification(sc.BucketNotificationGet(ctx, bucket)))
+		},
+	}
+	bucketNotificationCmd.Flags().BoolVar(&notificationDelete, "delete", false, "delete bucket notifications")
+	rootCmd.AddCommand(bucketNotificationCmd)
+
+	lifecycleDelete := false
+	lifecycleRule := s3cli.LifecycleRuleOptions{}
+	bucketLifecycleCmd := &cobra.Command{
+		Use:   "lifecycle <bucket> [rules-file]",
+		Short: "bucket lifecycle",
+		Long: `get/put/delete bucket lifecycle rules usage:
+* get Bucket lifecycle rules
+	s3cli lifecycle bucket-name
+* put(replace) Bucket lifecycle rules of a JSON/YAML file
+	s3cli lifecycle bucket-name lifecycle.yaml
+* put(replace) Bucket lifecycle rules with a rule of flags
+	s3cli lifecycle bucket-name --expire-days 30 --abort-mpu-days 7 --prefix logs/
+	s3cli lifecycle bucket-name --noncurrent-transition-days 30 --noncurrent-storage-class GLACIER
+* delete Bucket lifecycle rules
+	s3cli lifecycle bucket-name --delete
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, _ := sc.SplitKeyValue(args[0], "/")
+			ruleFlags := false
+			for _, name := range []string{"expire-days", "abort-mpu-days", "noncurrent-transition-days"} {
+				ruleFlags = ruleFlags || cmd.Flags().Changed(name)
+			}
+			for _, name := range []string{"id", "prefix", "noncurrent-storage-class"} {
+				if cmd.Flags().Changed(name) && !ruleFlags {
+					return sc.ErrorHandler(fmt.Errorf("--%s requires a rule action flag(--expire-days, --abort-mpu-days or --noncurrent-transition-days)", name))
+				}
+			}
+			switch {
+			case lifecycleDelete:
+				if len(args) > 1 || ruleFlags {
+					return sc.ErrorHandler(errors.New("--delete takes no rules"))
+				}
+				return sc.ErrorHandler(sc.BucketLifecycleDelete(ctx, bucket))
+			case len(args) > 1 && ruleFlags:
+				return sc.ErrorHandler(errors.New("rules file and rule flags are exclusive"))
+			case len(args) > 1:
+				cfg, err := s3cli.LoadLifecycleConfig(args[1])
+				if err != nil {
+					return sc.ErrorHandler(err)
+				}
+				return sc.ErrorHandler(sc.BucketLifecyclePut(ctx, bucket, cfg))
+			case ruleFlags:
+				cfg := &s3.BucketLifecycleConfiguration{Rules: []*s3.LifecycleRule{lifecycleRule.Rule()}}
+				return sc.ErrorHandler(sc.BucketLifecyclePut(ctx, bucket, cfg))
+			}
+			return sc.ErrorHandler(sc.RenderBucketLifecycle(sc.BucketLifecycleGet(ctx, bucket)))
+		},
+	}
+	bucketLifecycleCmd.Flags().BoolVar(&lifecycleDelete, "delete", false, "delete bucket lifecycle rules")
+	bucketLifecycleCmd.Flags().StringVar(&lifecycleRule.ID, "id", "s3cli", "ID of the rule of flags")
+	bucketLifecycleCmd.Flags().StringVar(&lifecycleRule.Prefix, "prefix", "", "Object key prefix of the rule of flags")
+	bucketLifecycleCmd.Flags().Int64Var(&lifecycleRule.ExpireDays, "expire-days", 0, "expire Objects N days after creation")
+	bucketLifecycleCmd.Flags().Int64Var(&lifecycleRule.AbortMPUDays, "abort-mpu-days", 0, "abort incomplete MPU N days after initiation")
+	bucketLifecycleCmd.Flags().Int64Var(&lifecycleRule.NoncurrentTransitionDays, "noncurrent-transition-days", 0, "transition noncurrent versions N days after they become noncurrent")
+	bucketLifecycleCmd.Flags().StringVar(&lifecycleRule.NoncurrentStorageClass, "noncurrent-storage-class", s3.TransitionStorageClassGlacier, "storage class of noncurrent version transition")
+	rootCmd.AddCommand(bucketLifecycleCmd)
+
+	replicationDelete := false
+	bucketReplicationCmd := &cobra.Command{
+		Use:   "replication <bucket> [config-file]",
+		Short: "bucket replication",
+		Long: `get/put/delete bucket replication configuration usage:
+* get Bucket replication configuration
+	s3cli replication bucket-name
+* put(replace) Bucket replication configuration of a JSON/YAML file(validated before put)
+	s3cli replication bucket-name replication.yaml
+* delete Bucket replication configuration
+	s3cli replication bucket-name --delete
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, _ := sc.SplitKeyValue(args[0], "/")
+			if replicationDelete {
+				if len(args) > 1 {
+					return sc.ErrorHandler(errors.New("--delete takes no config file"))
+				}
+				return sc.ErrorHandler(sc.BucketReplicationDelete(ctx, bucket))
+			}
+			if len(args) > 1 {
+				cfg, err := s3cli.LoadReplicationConfig(args[1])
+				if err != nil {
+					return sc.ErrorHandler(err)
+				}
+				return sc.ErrorHandler(sc.BucketReplicationPut(ctx, bucket, cfg))
+			}
+			return sc.ErrorHandler(sc.RenderBucketReplication(sc.BucketReplicationGet(ctx, bucket)))
+		},
+	}
+	bucketReplicationCmd.Flags().BoolVar(&replicationDelete, "delete", false, "delete bucket replication configuration")
+	rootCmd.AddCommand(bucketReplicationCmd)
+
+	tagDelete := false
+	tagVersion := ""
+	tagCmd := &cobra.Command{
+		Use:   "tag <bucket[/key]> [Key=Value ...]",
+		Short: "bucket/Object tagging",
+		Long: `get/put/delete Bucket or Object tags usage:
+* get Bucket tags
+	s3cli tag bucket-name
+* put(replace) Bucket tags
+	s3cli tag bucket-name project=a team=b
+* delete Bucket tags
+	s3cli tag bucket-name --delete
+* get Object tags
+	s3cli tag bucket-name/key
+* put(replace) tags of a Object version
+	s3cli tag bucket-name/key project=a --version version-id
+* delete Object tags
+	s3cli tag bucket-name/key --delete
+`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			tags, err := s3cli.ParseTags(args[1:])
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			if tagDelete && len(tags) > 0 {
+				return sc.ErrorHandler(errors.New("--delete takes no tags"))
+			}
+			if key == "" {
+				if tagVersion != "" {
+					return sc.ErrorHandler(errors.New("--version requires a Object key"))
+				}
+				if tagDelete {
+					return sc.ErrorHandler(sc.BucketTaggingDelete(ctx, bucket))
+				} else if len(tags) > 0 {
+					return sc.ErrorHandler(sc.BucketTaggingPut(ctx, bucket, tags))
+				}
+				return sc.ErrorHandler(sc.RenderBucketTagging(sc.BucketTaggingGet(ctx, bucket)))
+			}
+			if tagDelete {
+				return sc.ErrorHandler(sc.ObjectTaggingDelete(ctx, bucket, key, tagVersion))
+			} else if len(tags) > 0 {
+				return sc.ErrorHandler(sc.ObjectTaggingPut(ctx, bucket, key, tagVersion, tags))
+			}
+			return sc.ErrorHandler(sc.RenderObjectTagging(sc.ObjectTaggingGet(ctx, bucket, key, tagVersion)))
+		},
+	}
+	tagCmd.Flags().BoolVar(&tagDelete, "delete", false, "delete tags")
+	tagCmd.Flags().StringVar(&tagVersion, "version", "", "Object version ID")
+	rootCmd.AddCommand(tagCmd)
+
 	// object upload(put)
+	uploadRecursive := false
+	uploadDirOpt := s3cli.UploadDirOptions{}
 	uploadObjectCmd := &cobra.Command{
 		Use:     "upload <bucket[/key]> [file ...]",
 		Aliases: []string{"put"},
@@ -404,16 +736,25 @@ EnvVar:
 	s3cli upload bucket-name/dir2/ *.txt
 * upload a Object with given contents
 	s3cli upload bucket-name/key --data text-content
+* upload a directory(recursively) with specified common prefix(dir/), skip files match patterns in ./dir/.s3ignore
+	s3cli upload -r bucket-name/dir/ ./dir
+	s3cli upload -r bucket-name/dir/ ./dir --exclude '*.tmp' --concurrency 16
+* upload a file with tags
+	s3cli upload bucket-name/key /path/to/file --tag project=a --tag team=b
+* upload a website redirect Object
+	s3cli upload bucket-name/old.html --website-redirect /new.html
+* upload a file with Object Lock retention and legal hold
+	s3cli upload bucket-name/key /path/to/file --lock-mode GOVERNANCE --lock-retain-until 2030-01-01 --legal-hold
 * presign(V4) a PUT Object URL
 	s3cli upload bucket-name/key --presign`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			var fd *os.File
 			stream := cmd.Flag("stream").Changed
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			var metadata map[string]*string
 			for _, v := range objectMetadata {
-				k, v := sc.splitKeyValue(v, ":")
+				k, v := sc.SplitKeyValue(v, ":")
 				if k != "" && v != "" {
 					if metadata == nil {
 						metadata = make(map[string]*string)
@@ -421,11 +762,31 @@ EnvVar:
 					metadata[k] = &v
 				}
 			}
+			opt, err := objectOptions(cmd)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			sc.BodyBufferSize = uploadDirOpt.PartSize << 20
+			if uploadRecursive {
+				if len(args) < 2 {
+					return sc.ErrorHandler(errors.New("no directory to upload"))
+				}
+				uploadDirOpt.MPUThreshold <<= 20
+				uploadDirOpt.PartSize <<= 20
+				uploadDirOpt.ObjectOptions = opt
+				for _, dir := range args[1:] {
+					err = sc.RenderUpload(sc.UploadDir(ctx, bucket, key, dir, objectContentType, metadata, uploadDirOpt))
+					if err != nil {
+						return sc.ErrorHandler(err)
+					}
+				}
+				return nil
+			}
 			if len(args) < 2 { // upload one Object
 				if objectContentData != "" { // upload a Object with given content
-					err = sc.putObject(ctx, bucket, key, objectContentType, metadata, stream, strings.NewReader(objectContentData))
+					_, err = sc.PutObject(ctx, bucket, key, objectContentType, metadata, stream, strings.NewReader(objectContentData), opt)
 				} else { // upload a zero-size Object
-					err = sc.putObject(ctx, bucket, key, objectContentType, metadata, stream, fd)
+					_, err = sc.PutObject(ctx, bucket, key, objectContentType, metadata, stream, fd, opt)
 				}
 			} else if len(args) == 2 { // upload one file
 				if key == "" {
@@ -433,38 +794,47 @@ EnvVar:
 				}
 				fd, err = os.Open(args[1])
 				if err != nil {
-					return sc.errorHandler(err)
+					return sc.ErrorHandler(err)
 				}
 				defer fd.Close()
 				if objectContentType == "" {
 					objectContentType = mime.TypeByExtension(filepath.Ext(args[1]))
 				}
-				err = sc.putObject(ctx, bucket, key, objectContentType, metadata, stream, fd)
+				_, err = sc.PutObject(ctx, bucket, key, objectContentType, metadata, stream, fd, opt)
 			} else { // upload files
 				for _, v := range args[1:] {
 					fd, err = os.Open(v)
 					if err != nil {
-						return sc.errorHandler(err)
+						return sc.ErrorHandler(err)
 					}
 					if objectContentType == "" {
 						objectContentType = mime.TypeByExtension(filepath.Ext(args[1]))
 					}
 					newKey := key + filepath.Base(v)
-					err = sc.putObject(ctx, bucket, newKey, objectContentType, metadata, stream, fd)
+					_, err = sc.PutObject(ctx, bucket, newKey, objectContentType, metadata, stream, fd, opt)
 					if err != nil {
 						fd.Close()
-						return sc.errorHandler(err)
+						return sc.ErrorHandler(err)
 					}
 					fd.Close()
 				}
 			}
-			return sc.errorHandler(err)
+			return sc.ErrorHandler(err)
 		},
 	}
 	uploadObjectCmd.Flags().StringVar(&objectContentType, "content-type", "", "Object content-type(auto detect if not specified)")
 	uploadObjectCmd.Flags().StringVar(&objectContentData, "data", "", "Object content")
 	uploadObjectCmd.Flags().BoolP("stream", "", false, "stream mode(header Transfer-Encoding: chunked)")
 	uploadObjectCmd.Flags().StringArrayVar(&objectMetadata, "md", nil, "Object user metadata(format Key:Value)")
+	uploadObjectCmd.Flags().StringArray("tag", nil, "Object tag(format Key=Value)")
+	uploadObjectCmd.Flags().String("website-redirect", "", "redirect website requests of the Object to a key(/path) or URL")
+	uploadObjectCmd.Flags().BoolVarP(&uploadRecursive, "recursive", "r", false, "upload directory(s) recursively")
+	uploadObjectCmd.Flags().StringArrayVar(&uploadDirOpt.Filter.Exclude, "exclude", nil, "skip files match glob pattern in recursive upload")
+	uploadObjectCmd.Flags().Int64Var(&uploadDirOpt.MPUThreshold, "mpu-threshold", 64, "upload files larger than mpu-threshold(MB) with MPU in recursive upload")
+	uploadObjectCmd.Flags().Int64Var(&uploadDirOpt.PartSize, "part-size", s3manager.MinUploadPartSize>>20, "MPU part-size in MB(also the max size of stdin buffered to retry)")
+	uploadObjectCmd.Flags().IntVarP(&uploadDirOpt.Concurrency, "concurrency", "c", 8, "number of concurrent file uploads in recursive upload")
+	uploadObjectCmd.Flags().IntVar(&uploadDirOpt.PartConcurrency, "part-concurrency", s3manager.DefaultUploadConcurrency, "number of concurrent parts of a MPU uploaded file in recursive upload")
+	addLockFlags(uploadObjectCmd)
 	rootCmd.AddCommand(uploadObjectCmd)
 
 	headCmd := &cobra.Command{
@@ -474,20 +844,28 @@ EnvVar:
 * head a Bucket
 	s3cli head bucket-name
 * head a Object
-	s3cli head bucket-name/key`,
+	s3cli head bucket-name/key
+* show replication status(PENDING, COMPLETE, FAILED or REPLICA) of a Object
+	s3cli head bucket-name/key --replication-status`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			if key != "" {
-				mt := cmd.Flag("mtime").Changed
-				mts := cmd.Flag("mtimestamp").Changed
-				return sc.errorHandler(sc.headObject(ctx, bucket, key, mt, mts))
+				opt := s3cli.HeadOptions{
+					Mtime:             cmd.Flag("mtime").Changed,
+					Mtimestamp:        cmd.Flag("mtimestamp").Changed,
+					ReplicationStatus: cmd.Flag("replication-status").Changed,
+				}
+				out, err := sc.HeadObject(ctx, bucket, key)
+				return sc.ErrorHandler(sc.RenderHeadObject(key, opt, out, err))
 			}
-			return sc.errorHandler(sc.bucketHead(ctx, bucket))
+			out, err := sc.BucketHead(ctx, bucket)
+			return sc.ErrorHandler(sc.RenderBucketHead(bucket, out, err))
 		},
 	}
 	headCmd.Flags().BoolP("mtimestamp", "", false, "show Object mtimestamp")
 	headCmd.Flags().BoolP("mtime", "", false, "show Object mtime")
+	headCmd.Flags().BoolP("replication-status", "", false, "show Object x-amz-replication-status")
 	rootCmd.AddCommand(headCmd)
 
 	aclCmd := &cobra.Command{
@@ -507,10 +885,10 @@ EnvVar:
 `,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			if key != "" { // Object ACL
 				if len(args) == 1 {
-					return sc.errorHandler(sc.getObjectACL(ctx, bucket, key))
+					return sc.ErrorHandler(sc.RenderObjectACL(sc.GetObjectACL(ctx, bucket, key)))
 				}
 				var acl string
 				switch args[1] {
@@ -529,13 +907,13 @@ EnvVar:
 				case s3.ObjectCannedACLBucketOwnerFullControl:
 					acl = s3.ObjectCannedACLBucketOwnerFullControl
 				default:
-					return sc.errorHandler(fmt.Errorf("invalid ACL: %s", args[1]))
+					return sc.ErrorHandler(fmt.Errorf("invalid ACL: %s", args[1]))
 				}
-				return sc.errorHandler(sc.setObjectACL(ctx, bucket, key, acl))
+				return sc.ErrorHandler(sc.SetObjectACL(ctx, bucket, key, acl))
 			}
 			// Bucket ACL
 			if len(args) == 1 {
-				return sc.errorHandler(sc.bucketACLGet(ctx, args[0]))
+				return sc.ErrorHandler(sc.RenderBucketACL(sc.BucketACLGet(ctx, args[0])))
 			}
 			var acl string
 			switch args[1] {
@@ -548,9 +926,9 @@ EnvVar:
 			case s3.BucketCannedACLAuthenticatedRead:
 				acl = s3.BucketCannedACLAuthenticatedRead
 			default:
-				return sc.errorHandler(fmt.Errorf("invalid ACL: %s", args[1]))
+				return sc.ErrorHandler(fmt.Errorf("invalid ACL: %s", args[1]))
 			}
-			return sc.errorHandler(sc.bucketACLSet(ctx, args[0], acl))
+			return sc.ErrorHandler(sc.BucketACLSet(ctx, args[0], acl))
 		},
 	}
 	rootCmd.AddCommand(aclCmd)
@@ -581,26 +959,28 @@ EnvVar:
 			if len(args) == 1 { // list Objects
 				stime, err := time.Parse("2006-01-02T15:04:05Z", cmd.Flag("start-time").Value.String())
 				if err != nil {
-					return sc.errorHandler(fmt.Errorf("invalid start-time %s, error %s", cmd.Flag("start-time").Value.String(), err))
+					return sc.ErrorHandler(fmt.Errorf("invalid start-time %s, error %s", cmd.Flag("start-time").Value.String(), err))
 				}
 				etime, err := time.Parse("2006-01-02T15:04:05Z", cmd.Flag("end-time").Value.String())
 				if err != nil {
-					return sc.errorHandler(fmt.Errorf("invalid end-time %s, error %s", cmd.Flag("end-time").Value.String(), err))
+					return sc.ErrorHandler(fmt.Errorf("invalid end-time %s, error %s", cmd.Flag("end-time").Value.String(), err))
 				}
 
-				bucket, prefix := sc.splitKeyValue(args[0], "/")
+				bucket, prefix := sc.SplitKeyValue(args[0], "/")
 				if args[0] == bucket+"/" {
 					bucket = args[0]
 				}
 				if cmd.Flag("all").Changed {
-					return sc.errorHandler(sc.listAllObjects(ctx, bucket, prefix, delimiter, index, stime, etime))
+					out, err := sc.ListAllObjects(ctx, bucket, prefix, delimiter, stime, etime)
+					return sc.ErrorHandler(sc.RenderObjects(out, index, err))
 				}
 				marker := cmd.Flag("marker").Value.String()
-				return sc.errorHandler(sc.listObjects(ctx, bucket, prefix, delimiter, marker, listMaxKeys, index, stime, etime))
+				out, err := sc.ListObjects(ctx, bucket, prefix, delimiter, marker, listMaxKeys, stime, etime)
+				return sc.ErrorHandler(sc.RenderObjects(out, index, err))
 			}
 
 			// list all my Buckets
-			return sc.errorHandler(sc.bucketList(ctx))
+			return sc.ErrorHandler(sc.RenderBucketList(sc.BucketList(ctx)))
 		},
 	}
 	listObjectCmd.Flags().StringP("marker", "m", "", "marker")
@@ -636,27 +1016,29 @@ EnvVar:
 			if len(args) == 1 { // list Objects
 				stime, err := time.Parse("2006-01-02T15:04:05Z", cmd.Flag("start-time").Value.String())
 				if err != nil {
-					return sc.errorHandler(fmt.Errorf("invalid start-time %s, error %s", cmd.Flag("start-time").Value.String(), err))
+					return sc.ErrorHandler(fmt.Errorf("invalid start-time %s, error %s", cmd.Flag("start-time").Value.String(), err))
 				}
 				etime, err := time.Parse("2006-01-02T15:04:05Z", cmd.Flag("end-time").Value.String())
 				if err != nil {
-					return sc.errorHandler(fmt.Errorf("invalid enf-time %s, error %s", cmd.Flag("end-time").Value.String(), err))
+					return sc.ErrorHandler(fmt.Errorf("invalid enf-time %s, error %s", cmd.Flag("end-time").Value.String(), err))
 				}
 
-				bucket, prefix := sc.splitKeyValue(args[0], "/")
+				bucket, prefix := sc.SplitKeyValue(args[0], "/")
 				if args[0] == bucket+"/" {
 					bucket = args[0]
 				}
 				if cmd.Flag("all").Changed {
-					return sc.errorHandler(sc.listAllObjectsV2(ctx, bucket, prefix, delimiter, index, fetchOwner, stime, etime))
+					out, err := sc.ListAllObjectsV2(ctx, bucket, prefix, delimiter, fetchOwner, stime, etime)
+					return sc.ErrorHandler(sc.RenderObjectsV2(out, index, err))
 				}
 
 				marker := cmd.Flag("marker").Value.String()
-				return sc.errorHandler(sc.listObjectsV2(ctx, bucket, prefix, delimiter, marker, listMaxKeys, index, fetchOwner, stime, etime))
+				out, err := sc.ListObjectsV2(ctx, bucket, prefix, delimiter, marker, listMaxKeys, fetchOwner, stime, etime)
+				return sc.ErrorHandler(sc.RenderObjectsV2(out, index, err))
 			}
 
 			// list all my Buckets
-			return sc.errorHandler(sc.bucketList(ctx))
+			return sc.ErrorHandler(sc.RenderBucketList(sc.BucketList(ctx)))
 		},
 	}
 	listObjectV2Cmd.Flags().StringP("marker", "m", "", "marker")
@@ -680,8 +1062,8 @@ EnvVar:
 	s3cli lv bucket-name/prefix`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, prefix := sc.splitKeyValue(args[0], "/")
-			return sc.errorHandler(sc.listObjectVersions(ctx, bucket, prefix))
+			bucket, prefix := sc.SplitKeyValue(args[0], "/")
+			return sc.ErrorHandler(sc.RenderObjectVersions(sc.ListObjectVersions(ctx, bucket, prefix)))
 		},
 	}
 	rootCmd.AddCommand(listVersionCmd)
@@ -694,15 +1076,19 @@ EnvVar:
 * delete a Object Version
 	s3cli delete-version bucket-name/key --id version-id
 * delete all Objects Versions with specified prefix
-	s3cli delete-version bucket-name/prefix`,
+	s3cli delete-version bucket-name/prefix
+* delete a Object Version under GOVERNANCE retention
+	s3cli delete-version bucket-name/key --id version-id --bypass-governance`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, prefix := sc.splitKeyValue(args[0], "/")
+			bucket, prefix := sc.SplitKeyValue(args[0], "/")
 			version := cmd.Flag("id").Value.String()
-			return sc.errorHandler(sc.deleteObjectVersion(ctx, bucket, prefix, version))
+			bypass, _ := cmd.Flags().GetBool("bypass-governance")
+			return sc.ErrorHandler(sc.RenderDeleted(sc.DeleteObjectVersion(ctx, bucket, prefix, version, bypass)))
 		},
 	}
 	deleteVersionCmd.Flags().StringP("id", "", "", "Object versionID to delete")
+	deleteVersionCmd.Flags().Bool("bypass-governance", false, "bypass GOVERNANCE mode Object Lock retention")
 	rootCmd.AddCommand(deleteVersionCmd)
 
 	restoreObjectCmd := &cobra.Command{
@@ -717,13 +1103,13 @@ EnvVar:
 `,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			version := ""
 			if len(args) > 1 {
 				version = args[1]
 			}
-			err := sc.restoreObject(ctx, bucket, key, version)
-			return sc.errorHandler(err)
+			err := sc.RestoreObject(ctx, bucket, key, version)
+			return sc.ErrorHandler(err)
 		},
 	}
 	rootCmd.AddCommand(restoreObjectCmd)
@@ -737,22 +1123,58 @@ EnvVar:
 	s3cli download bucket-name/key
 * download Objects to ./
 	s3cli download bucket-name/key key2 key3
+* download all Objects with prefix(dir/) to ./dest and recreate the key hierarchy
+	s3cli download --recursive bucket-name/dir/ ./dest
+* download all Objects with prefix(dir) to ./dest/dir
+	s3cli download --recursive bucket-name/dir ./dest --overwrite
+* download a Object with 8 concurrent ranged GETs of 16MB,
+  rerun the same command to resume an interrupted download(from key.part and key.part.state)
+	s3cli download bucket-name/key --concurrency 8 --part-size 16
 * presign(V4) a download Object URL
 	s3cli download bucket-name/key --presign`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			overwrite, _ := cmd.Flags().GetBool("overwrite")
+			if recursive, _ := cmd.Flags().GetBool("recursive"); recursive {
+				if len(args) > 2 {
+					return sc.ErrorHandler(errors.New("only one destination directory allowed"))
+				}
+				dir := "."
+				if len(args) == 2 {
+					dir = args[1]
+				}
+				concurrency, _ := cmd.Flags().GetInt("concurrency")
+				return sc.ErrorHandler(sc.RenderDownload(sc.DownloadDir(ctx, bucket, key, dir, overwrite, concurrency)))
+			}
 			objRange := cmd.Flag("range").Value.String()
 			version := cmd.Flag("version").Value.String()
-			err := sc.getObject(ctx, bucket, key, objRange, version)
+			if cmd.Flag("part-size").Changed || cmd.Flag("concurrency").Changed {
+				if objRange != "" {
+					return sc.ErrorHandler(errors.New("--range can not be used with --part-size/--concurrency"))
+				}
+				partSize, _ := cmd.Flags().GetInt64("part-size")
+				concurrency, _ := cmd.Flags().GetInt("concurrency")
+				for i, k := range append([]string{key}, args[1:]...) {
+					if i > 0 {
+						version = ""
+					}
+					err := sc.RenderGet(sc.GetObjectRanged(ctx, bucket, k, version, filepath.Base(k), partSize<<20, concurrency, overwrite))
+					if err != nil {
+						return sc.ErrorHandler(err)
+					}
+				}
+				return nil
+			}
+			err := sc.RenderGet(sc.GetObject(ctx, bucket, key, objRange, version, overwrite))
 			if err != nil {
-				return sc.errorHandler(err)
+				return sc.ErrorHandler(err)
 			}
 			if len(args) > 1 {
 				for _, k := range args[1:] {
-					err := sc.getObject(ctx, bucket, k, "", "")
+					err := sc.RenderGet(sc.GetObject(ctx, bucket, k, "", "", overwrite))
 					if err != nil {
-						return sc.errorHandler(err)
+						return sc.ErrorHandler(err)
 					}
 				}
 			}
@@ -762,6 +1184,9 @@ EnvVar:
 	downloadObjectCmd.Flags().StringP("range", "r", "", "Object range to download, 0-64 means [0, 64]")
 	downloadObjectCmd.Flags().StringP("version", "", "", "Object version to download")
 	downloadObjectCmd.Flags().BoolP("overwrite", "w", false, "overwrite local file if exist")
+	downloadObjectCmd.Flags().BoolP("recursive", "", false, "download all Objects with prefix recursively")
+	downloadObjectCmd.Flags().IntP("concurrency", "c", 8, "number of concurrent Object downloads in recursive download, or concurrent ranged GETs of a Object")
+	downloadObjectCmd.Flags().Int64("part-size", s3manager.DefaultDownloadPartSize>>20, "ranged GET part-size in MB")
 	rootCmd.AddCommand(downloadObjectCmd)
 
 	catObjectCmd := &cobra.Command{
@@ -774,8 +1199,8 @@ EnvVar:
 		RunE: func(cmd *cobra.Command, args []string) error {
 			objRange := cmd.Flag("range").Value.String()
 			version := cmd.Flag("version").Value.String()
-			bucket, key := sc.splitKeyValue(args[0], "/")
-			return sc.errorHandler(sc.catObject(ctx, bucket, key, objRange, version))
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			return sc.ErrorHandler(sc.CatObject(ctx, os.Stdout, bucket, key, objRange, version))
 		},
 	}
 	catObjectCmd.Flags().StringP("range", "r", "", "Object range to cat, 0-64 means [0, 64]")
@@ -786,20 +1211,26 @@ EnvVar:
 		Use:     "rename <bucket/key> <bucket/key>",
 		Aliases: []string{"ren", "mv"},
 		Short:   "rename Object",
-		Long: `rename Bucket/key to Bucket/key usage:
+		Long: `rename(server-side copy and delete) Bucket/key to Bucket/key usage:
 * specify destination key
 	s3cli mv bucket-name/key1 bucket-name2/key2
 * default destionation key
-	s3cli mv bucket-name/key1 bucket-name2`,
+	s3cli mv bucket-name/key1 bucket-name2
+* rename all Objects with prefix(dir/) to prefix(dir2/)
+	s3cli mv bucket-name/dir/ bucket-name2/dir2/
+* rename all Objects with prefix(dir/) to another Bucket
+	s3cli mv bucket-name/dir/ bucket-name2 --concurrency 16`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[1], "/")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			bucket, key := sc.SplitKeyValue(args[1], "/")
 			if key == "" {
-				_, key = sc.splitKeyValue(args[0], "/")
+				_, key = sc.SplitKeyValue(args[0], "/")
 			}
-			return sc.errorHandler(sc.renameObject(ctx, args[0], bucket, key))
+			return sc.ErrorHandler(sc.RenderRename(sc.RenameObject(ctx, args[0], bucket, key, concurrency)))
 		},
 	}
+	renameObjectCmd.Flags().IntP("concurrency", "c", 8, "concurrency of Objects(prefix) or parts(Object larger than 5GiB) copy")
 	rootCmd.AddCommand(renameObjectCmd)
 
 	copyObjectCmd := &cobra.Command{
@@ -812,12 +1243,25 @@ EnvVar:
 * spedify destination Bucket
 	s3cli copy bucket-src/key-src bucket-dst/
 * spedify destionation Key
-	s3cli copy bucket-src/key-src key-dst`,
+	s3cli copy bucket-src/key-src key-dst
+* copy Object larger than 5GiB(multipart copy) with 16 concurrent 1024MB parts
+	s3cli copy bucket-src/key-src bucket-dst/key-dst --part-size 1024 -c 16
+* copy Object and replace its tags
+	s3cli copy bucket-src/key-src bucket-dst/key-dst --tag project=a --tag team=b
+* copy Object without tags
+	s3cli copy bucket-src/key-src bucket-dst/key-dst --tagging-directive REPLACE
+* copy Object from another S3 service(stream GetObject to MPU)
+	s3cli copy bucket-src/key-src bucket-dst/key-dst -e http://ecs:9020 --src-endpoint http://minio:9000 --src-ak ak --src-sk sk
+* copy Object to another S3 service
+	s3cli copy bucket-src/key-src bucket-dst/key-dst -e http://minio:9000 --dst-endpoint http://ecs:9020 --dst-ak ak --dst-sk sk
+* copy Object between two aliases
+	s3cli copy minio:bucket-src/key-src ecs:bucket-dst/key-dst
+	s3cli copy bucket-src/key-src bucket-dst/key-dst --src-alias minio --dst-alias ecs`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			var metadata map[string]*string
 			for _, v := range objectMetadata {
-				k, v := sc.splitKeyValue(v, ":")
+				k, v := sc.SplitKeyValue(v, ":")
 				if k != "" && v != "" {
 					if metadata == nil {
 						metadata = make(map[string]*string)
@@ -825,26 +1269,123 @@ EnvVar:
 					metadata[k] = &v
 				}
 			}
-			srcBucket, srcKey := sc.splitKeyValue(args[0], "/")
+			srcBucket, srcKey := sc.SplitKeyValue(args[0], "/")
 			dstBucket := ""
 			dstKey := ""
 			if !strings.Contains(args[1], "/") {
 				dstBucket = srcBucket
 				dstKey = args[1]
 			} else {
-				dstBucket, dstKey = sc.splitKeyValue(args[1], "/")
+				dstBucket, dstKey = sc.SplitKeyValue(args[1], "/")
 				if dstKey == "" {
 					dstKey = srcKey
 				}
 			}
 
-			return sc.errorHandler(sc.copyObject(ctx, args[0], dstBucket, dstKey, objectContentType, metadata))
+			partSize, _ := cmd.Flags().GetInt64("part-size")
+			concurrency, _ := cmd.Flags().GetInt("concurrency")
+			opt, err := objectOptions(cmd)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			src, err := newSourceCli(cmd, &sc, cliCfg, srcAliasName)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			dst, err := newServiceCli(cmd, "dst", &sc, cliCfg, "")
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			if dst != nil {
+				// the S3 service of command is the source unless --src-* set
+				if src == nil {
+					src = &sc
+				}
+				return sc.ErrorHandler(dst.RenderCopy(dst.CopyObjectFrom(ctx, src, srcBucket, srcKey, dstBucket, dstKey, objectContentType, metadata, partSize<<20, concurrency, opt)))
+			}
+			if src != nil {
+				return sc.ErrorHandler(sc.RenderCopy(sc.CopyObjectFrom(ctx, src, srcBucket, srcKey, dstBucket, dstKey, objectContentType, metadata, partSize<<20, concurrency, opt)))
+			}
+			return sc.ErrorHandler(sc.RenderCopy(sc.CopyObject(ctx, args[0], dstBucket, dstKey, objectContentType, metadata, partSize<<20, concurrency, opt)))
 		},
 	}
+	addSourceFlags(copyObjectCmd)
 	copyObjectCmd.Flags().StringArrayVar(&objectMetadata, "md", nil, "new Object user metadata(format Key:Value)")
 	copyObjectCmd.Flags().StringVar(&objectContentType, "content-type", "", "new Object content-type")
+	copyObjectCmd.Flags().StringArray("tag", nil, "new Object tag(format Key=Value), replace tags of source")
+	copyObjectCmd.Flags().String("tagging-directive", "", "COPY or REPLACE tags of source(default REPLACE with --tag, otherwise COPY)")
+	copyObjectCmd.Flags().Int64("part-size", s3cli.DefaultCopyPartSize>>20, "part-size(MB) of multipart copy(Object larger than 5GiB)")
+	copyObjectCmd.Flags().IntP("concurrency", "c", 8, "concurrency of multipart copy(Object larger than 5GiB)")
+	addLockFlags(copyObjectCmd)
 	rootCmd.AddCommand(copyObjectCmd)
 
+	syncOpt := s3cli.SyncOptions{}
+	syncCmd := &cobra.Command{
+		Use:   "sync <src> <dst>",
+		Short: "sync a local directory and Bucket/prefix",
+		Long: `sync a local directory and Bucket/prefix, or two Bucket/prefixes(only transfer files/Objects differ in size, mtime or ETag/MD5) usage:
+* upload a local directory to Bucket/prefix
+	s3cli sync ./dir bucket-name/prefix
+* download Bucket/prefix to a local directory
+	s3cli sync bucket-name/prefix ./dir
+* delete Objects not exist in local directory
+	s3cli sync ./dir bucket-name/prefix --delete
+* show what would be transferred or deleted
+	s3cli sync ./dir bucket-name/prefix --delete --dry-run
+* skip files match pattern(*.tmp) but always sync keep.tmp
+	s3cli sync ./dir bucket-name --exclude '*.tmp' --include keep.tmp
+* only sync jpg files
+	s3cli sync ./dir bucket-name --include '*.jpg'
+* upload to a Bucket named like an existing local directory
+	s3cli sync ./dir s3://backup
+* sync Bucket/prefix to another Bucket/prefix(server-side copy)
+	s3cli sync s3://bucket-src/prefix s3://bucket-dst/prefix
+* sync Bucket/prefix of another S3 service(stream GetObject to MPU)
+	s3cli sync bucket-src/prefix bucket-dst/prefix -e http://ecs:9020 --src-endpoint http://minio:9000 --src-ak ak --src-sk sk
+* sync Bucket/prefix to another S3 service with 16MB parts
+	s3cli sync bucket-src/prefix bucket-dst/prefix -e http://minio:9000 --dst-alias ecs --part-size 16
+
+* a local path starts with '.' or '/', a Bucket/prefix starts with s3:// or alias:
+* an unmarked argument is the opposite of the other one(both are Bucket/prefix with --src-*/--dst-*),
+  it's an error if both are unmarked or an unmarked Bucket/prefix exists locally`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src, err := newSourceCli(cmd, &sc, cliCfg, srcAliasName)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			dst, err := newServiceCli(cmd, "dst", &sc, cliCfg, "")
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			syncOpt.Source = src
+			syncOpt.PartSize <<= 20
+			// alias:bucket/prefix arguments are Bucket/prefix
+			for i := range args {
+				if aliasArgs[i] && !strings.HasPrefix(args[i], s3cli.S3Scheme) {
+					args[i] = s3cli.S3Scheme + args[i]
+				}
+			}
+			if dst != nil {
+				// the S3 service of command is the source unless --src-* set
+				if syncOpt.Source == nil {
+					syncOpt.Source = &sc
+				}
+				return sc.ErrorHandler(dst.RenderSync(dst.SyncDir(ctx, args[0], args[1], syncOpt)))
+			}
+			return sc.ErrorHandler(sc.RenderSync(sc.SyncDir(ctx, args[0], args[1], syncOpt)))
+		},
+	}
+	addSourceFlags(syncCmd)
+	syncCmd.Flags().BoolVar(&syncOpt.Delete, "delete", false, "delete destination files/Objects not exist in source")
+	syncCmd.Flags().BoolVar(&syncOpt.DryRun, "dry-run", false, "show what would be done without transfer or delete")
+	syncCmd.Flags().StringArrayVar(&syncOpt.Filter.Exclude, "exclude", nil, "skip files/Objects match glob pattern")
+	syncCmd.Flags().StringArrayVar(&syncOpt.Filter.Include, "include", nil, "sync files/Objects match glob pattern")
+	syncCmd.Flags().IntVarP(&syncOpt.Concurrency, "concurrency", "c", 8, "number of concurrent transfers")
+	syncCmd.Flags().Int64Var(&syncOpt.PartSize, "part-size", s3manager.DefaultUploadPartSize>>20, "part-size(MB) of Objects streamed from another S3 service")
+	syncCmd.Flags().IntVar(&syncOpt.PartConcurrency, "part-concurrency", s3manager.DefaultUploadConcurrency, "number of concurrent parts of a streamed or multipart copied Object")
+	rootCmd.AddCommand(syncCmd)
+
 	deleteObjectCmd := &cobra.Command{
 		Use:     "delete <bucket/key> [key...]",
 		Aliases: []string{"rm"},
@@ -859,29 +1400,33 @@ EnvVar:
 * delete Objects
 	s3cli delete bucket-name/key1 key2 key3 key4
 * delete all Objects with same Prefix
-	s3cli delete bucket-name/prefix --prefix`,
+	s3cli delete bucket-name/prefix --prefix
+* delete an Object under GOVERNANCE retention
+	s3cli delete bucket-name/key --bypass-governance`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			prefixMode := cmd.Flag("prefix").Changed
 			force := cmd.Flag("force").Changed
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bypass, _ := cmd.Flags().GetBool("bypass-governance")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			if len(args) > 1 {
 				args[0] = key
-				return sc.errorHandler(sc.deleteObjects(ctx, bucket, args))
+				return sc.ErrorHandler(sc.RenderDeleted(sc.DeleteObjects(ctx, bucket, args, bypass)))
 			}
 			if prefixMode {
-				return sc.errorHandler(sc.deletePrefix(ctx, bucket, key))
+				return sc.ErrorHandler(sc.RenderDeleted(sc.DeletePrefix(ctx, bucket, key, bypass)))
 			}
 			if key == "" {
-				return sc.errorHandler(sc.deleteBucketAndObjects(ctx, args[0], force))
+				return sc.ErrorHandler(sc.RenderDeleted(sc.DeleteBucketAndObjects(ctx, args[0], force, bypass)))
 			}
 
-			return sc.errorHandler(sc.deleteObject(ctx, bucket, key))
+			return sc.ErrorHandler(sc.RenderDeleted(sc.DeleteObject(ctx, bucket, key, bypass)))
 
 		},
 	}
 	deleteObjectCmd.Flags().BoolP("force", "", false, "delete Bucket and all Objects")
 	deleteObjectCmd.Flags().BoolP("prefix", "", false, "delete all Objects start with specified prefix")
+	deleteObjectCmd.Flags().Bool("bypass-governance", false, "bypass GOVERNANCE mode Object Lock retention")
 	rootCmd.AddCommand(deleteObjectCmd)
 
 	mpuCreateCmd := &cobra.Command{
@@ -893,14 +1438,14 @@ EnvVar:
 	s3cli mpu-init bucket-name/key`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
-			return sc.errorHandler(sc.mpuCreate(ctx, bucket, key))
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			return sc.ErrorHandler(sc.RenderMPUCreate(sc.MPUCreate(ctx, bucket, key)))
 		},
 	}
 	rootCmd.AddCommand(mpuCreateCmd)
 
 	mpuUploadCmd := &cobra.Command{
-		Use:     "mpu-upload <bucket/key> <UploadId> <part-num:file>",
+		Use:     "mpu-upload <bucket/key> <UploadId> [<part-num:file> ...]",
 		Short:   "mpu-upload MPU part(s)",
 		Aliases: []string{"mu"},
 		Long: `upload a MPU Part usage:
@@ -909,26 +1454,58 @@ EnvVar:
 * upload MPU part2
 	s3cli mpu-upload bucket-name/key UploadId 2:localfile2
 * upload MPU part3 and part4
-	s3cli mpu-upload bucket-name/key UploadId 3:localfile3 4:localfile4`,
-		Args: cobra.MinimumNArgs(3),
+	s3cli mpu-upload bucket-name/key UploadId 3:localfile3 4:localfile4
+* upload all parts(8MB byte ranges) of a file, print part-num:etag manifest
+	s3cli mpu-upload bucket-name/key UploadId --file big.bin --part-size 8M
+* upload part3 and part5 to part9(8MB byte ranges) of a file
+	s3cli mpu-upload bucket-name/key UploadId --file big.bin --part-size 8M --parts 3,5-9`,
+		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			if filename, _ := cmd.Flags().GetString("file"); filename != "" {
+				if len(args) > 2 {
+					return sc.ErrorHandler(errors.New("--file conflicts with part-num:file"))
+				}
+				partSize, err := s3cli.ParseSize(cmd.Flag("part-size").Value.String())
+				if err != nil {
+					return sc.ErrorHandler(err)
+				}
+				var partNums []int64
+				if v, _ := cmd.Flags().GetString("parts"); v != "" {
+					if partNums, err = s3cli.ParsePartNumbers(v); err != nil {
+						return sc.ErrorHandler(err)
+					}
+				}
+				concurrency, _ := cmd.Flags().GetInt("concurrency")
+				// exit non-zero if any part failed
+				cmd.SilenceUsage, cmd.SilenceErrors = true, true
+				return sc.RenderPartUpload(sc.MPUUploadFile(ctx, bucket, key, args[1], filename, partSize, partNums, concurrency))
+			}
+			if len(args) < 3 {
+				return sc.ErrorHandler(errors.New("part-num:file or --file required"))
+			}
 			files := map[int64]string{}
 			for _, v := range args[2:] {
-				i, filename := sc.splitKeyValue(v, ":")
+				i, filename := sc.SplitKeyValue(v, ":")
 				if filename == "" {
-					return sc.errorHandler(fmt.Errorf("unknown filename: %s", filename))
+					return sc.ErrorHandler(fmt.Errorf("unknown filename: %s", filename))
 				}
 				index, err := strconv.ParseInt(i, 10, 64)
 				if err != nil {
-					return sc.errorHandler(fmt.Errorf("invalid part-num: %v, error: %s", i, err))
+					return sc.ErrorHandler(fmt.Errorf("invalid part-num: %v, error: %s", i, err))
 				}
 				files[index] = filename
 			}
 
-			bucket, key := sc.splitKeyValue(args[0], "/")
-			return sc.errorHandler(sc.mpuUpload(ctx, bucket, key, args[1], files))
+			// exit non-zero if any part failed
+			cmd.SilenceUsage, cmd.SilenceErrors = true, true
+			return sc.RenderPartUpload(sc.MPUUpload(ctx, bucket, key, args[1], files))
 		},
 	}
+	mpuUploadCmd.Flags().String("file", "", "upload byte ranges of the file as parts")
+	mpuUploadCmd.Flags().String("part-size", "8M", "part-size of --file(K, M or G suffix, default unit MB)")
+	mpuUploadCmd.Flags().String("parts", "", "part numbers of --file to upload(e.g. 3,5-9), default all")
+	mpuUploadCmd.Flags().IntP("concurrency", "c", s3manager.DefaultUploadConcurrency, "number of concurrent part uploads")
 	rootCmd.AddCommand(mpuUploadCmd)
 
 	mpuAbortCmd := &cobra.Command{
@@ -940,14 +1517,14 @@ EnvVar:
 	s3cli mpu-abort bucket-name/key UploadId`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			if bucket == "" {
-				return sc.errorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
+				return sc.ErrorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
 			}
 			if key == "" {
-				return sc.errorHandler(fmt.Errorf("unknown key <bucket/key>(%v)", args[0]))
+				return sc.ErrorHandler(fmt.Errorf("unknown key <bucket/key>(%v)", args[0]))
 			}
-			return sc.errorHandler(sc.mpuAbort(ctx, bucket, key, args[1]))
+			return sc.ErrorHandler(sc.RenderMPUAbort(sc.MPUAbort(ctx, bucket, key, args[1])))
 		},
 	}
 	rootCmd.AddCommand(mpuAbortCmd)
@@ -961,40 +1538,78 @@ EnvVar:
 	s3cli mpu-list bucket-name/prefix`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, prefix := sc.splitKeyValue(args[0], "/")
+			bucket, prefix := sc.SplitKeyValue(args[0], "/")
 			if bucket == "" {
-				return sc.errorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
+				return sc.ErrorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
 			}
-			return sc.errorHandler(sc.mpuList(ctx, bucket, prefix))
+			return sc.ErrorHandler(sc.RenderUploads(sc.MPUList(ctx, bucket, prefix)))
 		},
 	}
 	rootCmd.AddCommand(mpuListCmd)
 
 	mpuCompleteCmd := &cobra.Command{
-		Use:     "mpu-complete <bucket/key> <UploadId> <part-etag> [<part-etag> ...]",
+		Use:     "mpu-complete <bucket/key> <UploadId> [<part-etag> ...]",
 		Short:   "complete a MPU request",
 		Aliases: []string{"mc"},
 		Long: `complete a mutiPartUpload request usage:
 * complete a MPU request
-	s3cli mpu-complete bucket-name/key UploadId etag01 etag02 etag03`,
-		Args: cobra.MinimumNArgs(3),
+	s3cli mpu-complete bucket-name/key UploadId etag01 etag02 etag03
+* complete a MPU request with part-num:etag manifest
+	s3cli mpu-complete bucket-name/key UploadId 1:etag01 3:etag03
+	s3cli mpu-complete bucket-name/key UploadId $(s3cli mpu-list-parts bucket-name/key UploadId)
+* complete a MPU request with all parts listed by server
+	s3cli mpu-complete bucket-name/key UploadId --auto`,
+		Args: cobra.MinimumNArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			if bucket == "" {
-				return sc.errorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
+				return sc.ErrorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
 			}
 			if key == "" {
-				return sc.errorHandler(fmt.Errorf("unknown key <bucket/key>(%v)", args[0]))
+				return sc.ErrorHandler(fmt.Errorf("unknown key <bucket/key>(%v)", args[0]))
+			}
+			if auto, _ := cmd.Flags().GetBool("auto"); auto {
+				if len(args) > 2 {
+					return sc.ErrorHandler(fmt.Errorf("--auto conflicts with part-etag"))
+				}
+				return sc.ErrorHandler(sc.RenderMPU(sc.MPUCompleteAuto(ctx, bucket, key, args[1])))
 			}
-			etags := make([]string, len(args)-2)
-			for i := range etags {
-				etags[i] = args[i+2]
+			if len(args) < 3 {
+				return sc.ErrorHandler(fmt.Errorf("part-etag required(or --auto)"))
 			}
-			return sc.errorHandler(sc.mpuComplete(ctx, bucket, key, args[1], etags))
+			parts, err := s3cli.CompletedParts(args[2:])
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			return sc.ErrorHandler(sc.RenderMPU(sc.MPUComplete(ctx, bucket, key, args[1], parts)))
 		},
 	}
+	mpuCompleteCmd.Flags().Bool("auto", false, "complete with all parts listed by server")
 	rootCmd.AddCommand(mpuCompleteCmd)
 
+	mpuListPartsCmd := &cobra.Command{
+		Use:     "mpu-list-parts <bucket/key> <UploadId>",
+		Aliases: []string{"mlp"},
+		Short:   "list parts of a MPU request",
+		Long: `list uploaded parts of a mutiPartUpload request usage:
+* list parts(part-num:etag manifest)
+	s3cli mpu-list-parts bucket-name/key UploadId
+* list parts(part-num, last-modified, etag and size)
+	s3cli mpu-list-parts bucket-name/key UploadId -o line`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			if bucket == "" {
+				return sc.ErrorHandler(fmt.Errorf("unknown bucket <bucket/key>(%v)", args[0]))
+			}
+			if key == "" {
+				return sc.ErrorHandler(fmt.Errorf("unknown key <bucket/key>(%v)", args[0]))
+			}
+			return sc.ErrorHandler(sc.RenderParts(sc.MPUListParts(ctx, bucket, key, args[1])))
+		},
+	}
+	rootCmd.AddCommand(mpuListPartsCmd)
+
 	mpuCmd := &cobra.Command{
 		Use:   "mpu <bucket[/key]> [file]",
 		Short: "mpu Object(mpu-create, mpu-upload and mpu-complete)",
@@ -1003,14 +1618,16 @@ EnvVar:
 	s3cli mpu bucket /path/to/file
 * mpu a file to Bucket/Key
 	s3cli mpu bucket-name/key /path/to/file
+* resume an interrupted mpu(only upload parts missing on server)
+	s3cli mpu bucket-name/key /path/to/file --resume
 `,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
 			var fd *os.File
-			bucket, key := sc.splitKeyValue(args[0], "/")
+			bucket, key := sc.SplitKeyValue(args[0], "/")
 			var metadata map[string]*string
 			for _, v := range objectMetadata {
-				k, v := sc.splitKeyValue(v, ":")
+				k, v := sc.SplitKeyValue(v, ":")
 				if k != "" && v != "" {
 					if metadata == nil {
 						metadata = make(map[string]*string)
@@ -1025,7 +1642,7 @@ EnvVar:
 
 			fd, err = os.Open(args[1])
 			if err != nil {
-				return sc.errorHandler(err)
+				return sc.ErrorHandler(err)
 			}
 			defer fd.Close()
 			if objectContentType == "" {
@@ -1035,14 +1652,25 @@ EnvVar:
 				key = filepath.Base(args[1])
 			}
 
-			err = sc.mpu(ctx, bucket, key, objectContentType, partSize<<20, fd, metadata)
-
-			return sc.errorHandler(err)
+			opt, err := objectOptions(cmd)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			if resume, _ := cmd.Flags().GetBool("resume"); resume {
+				concurrency, _ := cmd.Flags().GetInt("concurrency")
+				return sc.ErrorHandler(sc.RenderMPU(sc.MPUResume(ctx, bucket, key, objectContentType, partSize<<20, fd, metadata, concurrency, opt)))
+			}
+			return sc.ErrorHandler(sc.RenderMPU(sc.MPU(ctx, bucket, key, objectContentType, partSize<<20, fd, metadata, opt)))
 		},
 	}
 	mpuCmd.Flags().StringVar(&objectContentType, "content-type", "", "Object content-type(auto detect if not specified)")
 	mpuCmd.Flags().Int64("part-size", s3manager.MinUploadPartSize>>20, "MPU part-size in MB")
 	mpuCmd.Flags().StringArrayVar(&objectMetadata, "md", nil, "Object user metadata(format Key:Value)")
+	mpuCmd.Flags().StringArray("tag", nil, "Object tag(format Key=Value)")
+	mpuCmd.Flags().String("website-redirect", "", "redirect website requests of the Object to a key(/path) or URL")
+	mpuCmd.Flags().Bool("resume", false, "resume the latest in-progress MPU of bucket/key")
+	mpuCmd.Flags().IntP("concurrency", "c", s3manager.DefaultUploadConcurrency, "number of concurrent part uploads in resume mode")
+	addLockFlags(mpuCmd)
 	rootCmd.AddCommand(mpuCmd)
 
 	//aws s3api --endpoint-url http://172.16.3.98:9020 --profile ak1 get-object-lock-configuration --bucket mybucket
@@ -1056,32 +1684,241 @@ EnvVar:
 `,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			err = sc.getObjectLockConfig(ctx, args[0])
-			return sc.errorHandler(err)
+			return sc.ErrorHandler(sc.RenderObjectLockConfig(sc.GetObjectLockConfig(ctx, args[0])))
 		},
 	}
 	rootCmd.AddCommand(getObjectLockConfigCmd)
 
+	lockMode := ""
+	lockDays, lockYears := int64(0), int64(0)
 	putObjectLockConfigCmd := &cobra.Command{
-		Use:     "put-object-lock-configuration <bucket>",
+		Use:     "put-object-lock-configuration <bucket> [Enabled]",
 		Aliases: []string{"polc"},
 		Short:   "put-object-lock-configuration Bucket",
 		Long: `put-object-lock-configuration Object usage:
 * Enable a Bucket lock configuration
 	s3cli put-object-lock-configuration bucket Enabled
-* Disable a Bucket lock configuration
-	s3cli put-object-lock-configuration bucket Disable
+* Enable a Bucket lock configuration with default retention
+	s3cli put-object-lock-configuration bucket --mode GOVERNANCE --days 30
+	s3cli put-object-lock-configuration bucket --mode COMPLIANCE --years 1
 `,
-		Args: cobra.ExactArgs(2),
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) (err error) {
-			err = sc.putObjectLockConfig(ctx, args[0], args[1])
-			return sc.errorHandler(err)
+			enabled := s3.ObjectLockEnabledEnabled
+			if len(args) > 1 {
+				enabled = args[1]
+			}
+			retention, err := s3cli.NewDefaultRetention(lockMode, lockDays, lockYears)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			err = sc.PutObjectLockConfig(ctx, args[0], enabled, retention)
+			return sc.ErrorHandler(err)
 		},
 	}
+	putObjectLockConfigCmd.Flags().StringVar(&lockMode, "mode", "", "default retention mode(GOVERNANCE or COMPLIANCE)")
+	putObjectLockConfigCmd.Flags().Int64Var(&lockDays, "days", 0, "default retention period in days")
+	putObjectLockConfigCmd.Flags().Int64Var(&lockYears, "years", 0, "default retention period in years")
 	rootCmd.AddCommand(putObjectLockConfigCmd)
 
+	retentionUntil := ""
+	retentionVersion := ""
+	retentionClear := false
+	retentionBypass := false
+	retentionCmd := &cobra.Command{
+		Use:   "retention <bucket/key> [GOVERNANCE|COMPLIANCE]",
+		Short: "Object Lock retention",
+		Long: `get/put Object Lock retention of a Object usage:
+* get retention of a Object
+	s3cli retention bucket-name/key
+* put retention of a Object version
+	s3cli retention bucket-name/key GOVERNANCE --until 2030-01-01 --version version-id
+	s3cli retention bucket-name/key COMPLIANCE --until 365d
+* extend or shorten GOVERNANCE retention
+	s3cli retention bucket-name/key GOVERNANCE --until 30d --bypass-governance
+* clear GOVERNANCE retention
+	s3cli retention bucket-name/key --clear --bypass-governance
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			if key == "" {
+				return sc.ErrorHandler(errors.New("retention requires a Object key"))
+			}
+			if retentionClear {
+				if len(args) > 1 || retentionUntil != "" {
+					return sc.ErrorHandler(errors.New("--clear takes no mode or --until"))
+				}
+				return sc.ErrorHandler(sc.ObjectRetentionPut(ctx, bucket, key, retentionVersion, "", time.Time{}, retentionBypass))
+			}
+			if len(args) == 1 && retentionUntil == "" {
+				return sc.ErrorHandler(sc.RenderObjectRetention(sc.ObjectRetentionGet(ctx, bucket, key, retentionVersion)))
+			}
+			if len(args) == 1 || retentionUntil == "" {
+				return sc.ErrorHandler(errors.New("put retention requires a mode and --until"))
+			}
+			until, err := s3cli.ParseRetainUntil(retentionUntil)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			return sc.ErrorHandler(sc.ObjectRetentionPut(ctx, bucket, key, retentionVersion, args[1], until, retentionBypass))
+		},
+	}
+	retentionCmd.Flags().StringVar(&retentionUntil, "until", "", "retain until date(2006-01-02, RFC3339 or days from now like 30d)")
+	retentionCmd.Flags().StringVar(&retentionVersion, "version", "", "Object version ID")
+	retentionCmd.Flags().BoolVar(&retentionClear, "clear", false, "clear retention")
+	retentionCmd.Flags().BoolVar(&retentionBypass, "bypass-governance", false, "bypass GOVERNANCE mode retention")
+	rootCmd.AddCommand(retentionCmd)
+
+	legalHoldVersion := ""
+	legalHoldCmd := &cobra.Command{
+		Use:   "legal-hold <bucket/key> [on|off]",
+		Short: "Object Lock legal hold",
+		Long: `get/put Object Lock legal hold of a Object usage:
+* get legal hold status of a Object
+	s3cli legal-hold bucket-name/key
+* turn on legal hold of a Object
+	s3cli legal-hold bucket-name/key on
+* turn off legal hold of a Object version
+	s3cli legal-hold bucket-name/key off --version version-id
+`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			bucket, key := sc.SplitKeyValue(args[0], "/")
+			if key == "" {
+				return sc.ErrorHandler(errors.New("legal-hold requires a Object key"))
+			}
+			if len(args) == 1 {
+				return sc.ErrorHandler(sc.RenderObjectLegalHold(sc.ObjectLegalHoldGet(ctx, bucket, key, legalHoldVersion)))
+			}
+			switch strings.ToLower(args[1]) {
+			case "on":
+				return sc.ErrorHandler(sc.ObjectLegalHoldPut(ctx, bucket, key, legalHoldVersion, true))
+			case "off":
+				return sc.ErrorHandler(sc.ObjectLegalHoldPut(ctx, bucket, key, legalHoldVersion, false))
+			}
+			return sc.ErrorHandler(fmt.Errorf("invalid legal hold %s(on or off)", args[1]))
+		},
+	}
+	legalHoldCmd.Flags().StringVar(&legalHoldVersion, "version", "", "Object version ID")
+	rootCmd.AddCommand(legalHoldCmd)
+
+	aliasCmd := &cobra.Command{
+		Use:   "alias",
+		Short: "manage aliases(endpoint and credentials) in config file",
+		Long: `manage aliases in config file(default ~/.config/s3cli/config.yaml) usage:
+* set(create or update) an alias
+	s3cli alias set minio -e http://host:9000 --ak ak --sk sk --path-style=false
+	s3cli alias set ecs -e https://host:9021 --ak ak --sk sk --v2sign --proxy none --dial-timeout 5
+	s3cli alias set private -e https://host:9021 --ak ak --sk sk --ca-bundle ca.pem --tls-min-version 1.2
+* list aliases
+	s3cli alias list
+* remove aliases
+	s3cli alias remove minio ecs
+* use an alias
+	s3cli ls minio:bucket-name
+	s3cli ls bucket-name --alias minio
+	s3cli copy minio:bucket/key ecs:bucket/key`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// no S3 client required
+			return sc.SetOutput(filter, outputTmpl, cmd.Flags().Changed("output"))
+		},
+	}
+	aliasSetCmd := &cobra.Command{
+		Use:   "set <name>",
+		Short: "set(create or update) an alias with --endpoint, --ak, --sk, --region, --v2sign, --path-style, --proxy, timeouts and TLS flags",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filename := s3cli.ConfigFilePath(configFile)
+			cfg, err := s3cli.LoadConfig(filename)
+			if err != nil {
+				return sc.ErrorHandler(err)
+			}
+			a := &s3cli.AliasConfig{}
+			if v, ok := cfg.Aliases[args[0]]; ok {
+				a = v
+			}
+			flags := cmd.Flags()
+			if flags.Changed("endpoint") {
+				a.Endpoint = sc.Endpoint
+			}
+			if flags.Changed("profile") {
+				a.Profile = sc.Profile
+			}
+			if flags.Changed("ak") {
+				a.AccessKey = sc.AccessKey
+			}
+			if flags.Changed("sk") {
+				a.SecretKey = sc.SecretKey
+			}
+			if flags.Changed("tk") {
+				a.SessionToken = sc.SessionToken
+			}
+			if flags.Changed("region") {
+				a.Region = sc.Region
+			}
+			if flags.Changed("v2sign") {
+				a.SignatureVersion = "v4"
+				if sc.V2Sign {
+					a.SignatureVersion = "v2"
+				}
+			}
+			if flags.Changed("path-style") {
+				a.PathStyle = aws.Bool(sc.PathStyle)
+			}
+			if flags.Changed("proxy") {
+				a.Proxy = sc.Proxy
+			}
+			if flags.Changed("dial-timeout") {
+				a.DialTimeout = sc.DialTimeout
+			}
+			if flags.Changed("response-header-timeout") {
+				a.ResponseHeaderTimeout = sc.ResponseHeaderTimeout
+			}
+			if flags.Changed("insecure") {
+				a.Insecure = aws.Bool(sc.Insecure)
+			}
+			if flags.Changed("ca-bundle") {
+				a.CABundle = sc.CABundle
+			}
+			if flags.Changed("client-cert") {
+				a.ClientCert = sc.ClientCert
+			}
+			if flags.Changed("client-key") {
+				a.ClientKey = sc.ClientKey
+			}
+			if flags.Changed("tls-server-name") {
+				a.TLSServerName = sc.TLSServerName
+			}
+			if flags.Changed("tls-min-version") {
+				a.TLSMinVersion = sc.TLSMinVersion
+			}
+			return sc.ErrorHandler(sc.AliasSet(filename, args[0], a))
+		},
+	}
+	aliasListCmd := &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls"},
+		Short:   "list aliases(secret keys are masked)",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sc.ErrorHandler(sc.RenderAliases(sc.AliasList(s3cli.ConfigFilePath(configFile))))
+		},
+	}
+	aliasRemoveCmd := &cobra.Command{
+		Use:     "remove <name> [name...]",
+		Aliases: []string{"rm"},
+		Short:   "remove aliases",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sc.ErrorHandler(sc.AliasRemove(s3cli.ConfigFilePath(configFile), args))
+		},
+	}
+	aliasCmd.AddCommand(aliasSetCmd, aliasListCmd, aliasRemoveCmd)
+	rootCmd.AddCommand(aliasCmd)
+
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
@@ -2,53 +2,87 @@ package main
 
 import (
 	"bytes"
-	"log"
-	mand "math/rand"
+	"errors"
 	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
-	"time"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/johannesboyne/gofakes3"
 	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/shvc/s3cli/pkg/s3cli"
 )
 
-var (
-	s3Backend *s3mem.Backend
-)
+// runMainEnv is set to run main(with the arguments of the test binary) instead of tests
+const runMainEnv = "S3CLI_TEST_RUN_MAIN"
 
-var s3cliTest = S3Cli{
-	accessKey: "my-ak",
-	secretKey: "my-sk",
-	region:    s3.BucketLocationConstraintCnNorth1,
-	Client:    nil,
+func TestMain(m *testing.M) {
+	if os.Getenv(runMainEnv) != "" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
 }
 
-func TestMain(m *testing.M) {
-	mand.Seed(time.Now().UTC().UnixNano())
-	// init fake s3
-	s3Backend = s3mem.New()
-	faker := gofakes3.New(s3Backend)
-	ts := httptest.NewServer(faker.Server())
+// runMain run s3cli with args in a child process, return its stdout, stderr and exit code
+func runMain(t *testing.T, args ...string) (string, string, int) {
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runMainEnv+"=1", "HOME="+t.TempDir())
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	err := cmd.Run()
+	code := 0
+	if e := (&exec.ExitError{}); errors.As(err, &e) {
+		code = e.ExitCode()
+	} else if err != nil {
+		t.Fatal("run s3cli failed: ", err)
+	}
+	return stdout.String(), stderr.String(), code
+}
+
+func Test_mpuUploadExitCode(t *testing.T) {
+	backend := s3mem.New()
+	if err := backend.CreateBucket("bucket-mpu"); err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(gofakes3.New(backend).Server())
 	defer ts.Close()
-	s3cliTest.endpoint = ts.URL
-	client, err := newS3Client(&s3cliTest)
+	client, err := s3cli.NewS3Client(&s3cli.S3Cli{Endpoint: ts.URL, AccessKey: "my-ak", SecretKey: "my-sk", Region: s3.BucketLocationConstraintCnNorth1, PathStyle: true})
 	if err != nil {
-		log.Fatal("newS3Client", err)
-		os.Exit(1)
+		t.Fatal("NewS3Client failed: ", err)
 	}
-	s3cliTest.Client = client
-	if err := s3Backend.CreateBucket(testBucketName); err != nil {
-		log.Fatal("backend CreateBucket error: ", err)
-		os.Exit(1)
+	mpu, err := client.CreateMultipartUpload(&s3.CreateMultipartUploadInput{Bucket: aws.String("bucket-mpu"), Key: aws.String("key")})
+	if err != nil {
+		t.Fatal("CreateMultipartUpload failed: ", err)
+	}
+	dir := t.TempDir()
+	part := filepath.Join(dir, "part1")
+	if err := os.WriteFile(part, []byte("part1"), 0644); err != nil {
+		t.Fatal(err)
 	}
+	global := []string{"-e", ts.URL, "--ak", "my-ak", "--sk", "my-sk", "--config", filepath.Join(dir, "config.yaml"), "--max-retries", "0"}
+	mpuUpload := append(global, "mpu-upload", "bucket-mpu/key", aws.StringValue(mpu.UploadId))
 
-	_, err = s3Backend.PutObject(testBucketName, testObjectKey, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
-	if err != nil {
-		log.Fatal("backend PutObject error: ", err)
-		os.Exit(1)
+	stdout, stderr, code := runMain(t, append(mpuUpload, "1:"+part)...)
+	if code != 0 {
+		t.Errorf("mpu-upload expect exit 0, got %d: %s", code, stderr)
+	}
+	if !strings.HasPrefix(stdout, "1:") {
+		t.Errorf("mpu-upload expect part manifest, got %q", stdout)
 	}
 
-	os.Exit(m.Run())
+	stdout, stderr, code = runMain(t, append(mpuUpload, "1:"+part, "2:"+filepath.Join(dir, "not-exist"))...)
+	if code == 0 {
+		t.Errorf("mpu-upload with a failed part expect non-zero exit")
+	}
+	if strings.Contains(stdout, "failed") {
+		t.Errorf("mpu-upload errors expect on stderr, got stdout %q", stdout)
+	}
+	if !strings.Contains(stderr, "1 of 2 parts upload failed") {
+		t.Errorf("mpu-upload expect failed parts on stderr, got %q", stderr)
+	}
 }
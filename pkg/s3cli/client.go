@@ -0,0 +1,157 @@
+package s3cli
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/corehandlers"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	DefaultDialTimeout           = 10
+	DefaultResponseHeaderTimeout = 20
+	// EndpointEnvVar is the env var of endpoint(only read if endpoint is not set)
+	EndpointEnvVar = "S3_ENDPOINT"
+)
+
+// NewS3Client create the S3 client of sc settings
+func NewS3Client(sc *S3Cli) (*s3.S3, error) {
+	if sc.Endpoint == "" {
+		sc.Endpoint = os.Getenv(EndpointEnvVar)
+	}
+	if sc.Endpoint == "" {
+		return nil, errors.New("unknown endpoint")
+	}
+
+	if !strings.HasPrefix(sc.Endpoint, "http://") && !strings.HasPrefix(sc.Endpoint, "https://") {
+		sc.Endpoint = "http://" + sc.Endpoint
+	}
+
+	if sc.AccessKey == "" {
+		sc.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		if sc.AccessKey == "" {
+			sc.AccessKey = os.Getenv("AWS_ACCESS_KEY")
+		}
+	}
+
+	if sc.SecretKey == "" {
+		sc.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if sc.SecretKey == "" {
+			sc.SecretKey = os.Getenv("AWS_SECRET_KEY")
+		}
+	}
+
+	if sc.AccessKey == "" && sc.SecretKey != "" {
+		return nil, errors.New("unknown accessKey")
+	}
+
+	if sc.AccessKey != "" && sc.SecretKey == "" {
+		return nil, errors.New("unknown secretKey")
+	}
+
+	if sc.SessionToken == "" {
+		sc.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+
+	if err := sc.tlsEnv(); err != nil {
+		return nil, err
+	}
+	tlsConfig, err := sc.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	tp := &http.Transport{
+		TLSClientConfig:       tlsConfig,
+		Dial:                  (&net.Dialer{Timeout: time.Duration(sc.DialTimeout) * time.Second}).Dial,
+		ResponseHeaderTimeout: time.Duration(sc.ResponseHeaderTimeout) * time.Second,
+		DisableKeepAlives:     sc.DisableKeepAlives,
+	}
+
+	if sc.Proxy != "" && sc.Proxy != "none" {
+		proxyURL, err := url.Parse(sc.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %s: %w", sc.Proxy, err)
+		}
+		tp.Proxy = http.ProxyURL(proxyURL)
+	} else if !sc.NoProxy && sc.Proxy != "none" {
+		tp.Proxy = http.ProxyFromEnvironment
+	}
+
+	cfg := &aws.Config{
+		Region:                        aws.String(sc.Region),
+		MaxRetries:                    aws.Int(sc.MaxRetries),
+		EnforceShouldRetryCheck:       aws.Bool(true),
+		S3ForcePathStyle:              aws.Bool(sc.PathStyle),
+		S3DisableContentMD5Validation: aws.Bool(sc.DisableContentMD5Validate),
+		HTTPClient: &http.Client{
+			Transport: tp,
+		},
+		EndpointResolver: endpoints.ResolverFunc(
+			func(service, region string, opts ...func(*endpoints.Options)) (endpoints.ResolvedEndpoint, error) {
+				return endpoints.ResolvedEndpoint{
+					URL:           sc.Endpoint,
+					SigningRegion: sc.Region,
+					SigningName:   service,
+					SigningMethod: "v4",
+				}, nil
+
+			}),
+	}
+
+	cfg = request.WithRetryer(cfg, newRetryer(sc.MaxRetries, sc.RetryMinDelay, sc.RetryMaxDelay, sc.verboseOutput()))
+
+	if sc.Profile != "" {
+		cfg.Credentials = credentials.NewSharedCredentials("", sc.Profile)
+		cfg.Credentials.Get()
+	} else if sc.AccessKey == "" && sc.SecretKey == "" {
+		cfg.Credentials = credentials.AnonymousCredentials
+	} else {
+		credentials.NewEnvCredentials()
+		cfg.Credentials = credentials.NewStaticCredentials(sc.AccessKey, sc.SecretKey, sc.SessionToken)
+	}
+	rootCAs := tlsConfig.RootCAs
+	sess := session.Must(session.NewSession(cfg))
+	// session replace RootCAs with env AWS_CA_BUNDLE, flag --ca-bundle takes precedence
+	if sc.CABundle != "" {
+		tp.TLSClientConfig.RootCAs = rootCAs
+	}
+	// wrap transport after session created, session requires *http.Transport to load AWS_CA_BUNDLE
+	if sc.Trace {
+		sess.Config.HTTPClient.Transport = newTraceTransport(tp, sc.jsonOutput())
+	}
+
+	if sc.Debug {
+		sess.Config.LogLevel = aws.LogLevel(aws.LogDebug)
+	}
+	svc := s3.New(sess)
+	if sc.V2Sign {
+		cred, _ := cfg.Credentials.Get()
+		svc.Handlers.Sign.Clear()
+		// auto fill content-length header
+		svc.Handlers.Sign.PushBackNamed(corehandlers.BuildContentLengthHandler)
+		svc.Handlers.Sign.PushBack(func(req *request.Request) {
+			if req.Config.Credentials == credentials.AnonymousCredentials {
+				return
+			}
+			if req.ExpireTime > 0 {
+				PresignV2(cred.AccessKeyID, cred.SecretAccessKey, req.ExpireTime, req.HTTPRequest)
+			} else {
+				SignV2(cred.AccessKeyID, cred.SecretAccessKey, req.HTTPRequest)
+			}
+		})
+	}
+
+	return svc, nil
+}
@@ -0,0 +1,277 @@
+package s3cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// ConfigEnvVar specify the config file(only read if flag --config is not set)
+	ConfigEnvVar = "S3CLI_CONFIG"
+	// aliasSep separate alias and bucket/key in alias:bucket/key
+	aliasSep = ":"
+)
+
+// aliasNameRegexp is the valid alias name
+var aliasNameRegexp = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// AliasConfig is a named S3 service(endpoint, credentials and client settings)
+type AliasConfig struct {
+	Endpoint              string `yaml:"endpoint" json:"endpoint"`
+	AccessKey             string `yaml:"accessKey,omitempty" json:"accessKey,omitempty"`
+	SecretKey             string `yaml:"secretKey,omitempty" json:"secretKey,omitempty"`
+	SessionToken          string `yaml:"sessionToken,omitempty" json:"sessionToken,omitempty"`
+	Profile               string `yaml:"profile,omitempty" json:"profile,omitempty"`
+	Region                string `yaml:"region,omitempty" json:"region,omitempty"`
+	SignatureVersion      string `yaml:"signatureVersion,omitempty" json:"signatureVersion,omitempty"` // v2 or v4
+	PathStyle             *bool  `yaml:"pathStyle,omitempty" json:"pathStyle,omitempty"`
+	Proxy                 string `yaml:"proxy,omitempty" json:"proxy,omitempty"`                                 // proxy URL, "none" to disable proxy
+	DialTimeout           int    `yaml:"dialTimeout,omitempty" json:"dialTimeout,omitempty"`                     // seconds
+	ResponseHeaderTimeout int    `yaml:"responseHeaderTimeout,omitempty" json:"responseHeaderTimeout,omitempty"` // seconds
+	Insecure              *bool  `yaml:"insecure,omitempty" json:"insecure,omitempty"`
+	CABundle              string `yaml:"caBundle,omitempty" json:"caBundle,omitempty"`
+	ClientCert            string `yaml:"clientCert,omitempty" json:"clientCert,omitempty"`
+	ClientKey             string `yaml:"clientKey,omitempty" json:"clientKey,omitempty"`
+	TLSServerName         string `yaml:"tlsServerName,omitempty" json:"tlsServerName,omitempty"`
+	TLSMinVersion         string `yaml:"tlsMinVersion,omitempty" json:"tlsMinVersion,omitempty"`
+}
+
+// validate check the alias settings
+func (a *AliasConfig) validate() error {
+	if a.Endpoint == "" {
+		return fmt.Errorf("unknown endpoint")
+	}
+	switch strings.ToLower(a.SignatureVersion) {
+	case "", "v2", "v4":
+	default:
+		return fmt.Errorf("invalid signatureVersion %s(v2 or v4)", a.SignatureVersion)
+	}
+	if a.DialTimeout < 0 || a.ResponseHeaderTimeout < 0 {
+		return fmt.Errorf("invalid timeout")
+	}
+	if a.TLSMinVersion != "" {
+		if _, err := parseTLSVersion(a.TLSMinVersion); err != nil {
+			return err
+		}
+	}
+	if (a.ClientCert == "") != (a.ClientKey == "") {
+		return fmt.Errorf("both clientCert and clientKey are required")
+	}
+	return nil
+}
+
+// Config is the s3cli config file
+type Config struct {
+	Aliases map[string]*AliasConfig `yaml:"aliases"`
+}
+
+// DefaultConfigFile return ~/.config/s3cli/config.yaml
+func DefaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".config", "s3cli", "config.yaml")
+}
+
+// ConfigFilePath return the config file of flag --config, env S3CLI_CONFIG or the default one
+func ConfigFilePath(flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if v := os.Getenv(ConfigEnvVar); v != "" {
+		return v
+	}
+	return DefaultConfigFile()
+}
+
+// LoadConfig read a config file, a not exist config file has no aliases
+func LoadConfig(filename string) (*Config, error) {
+	cfg := &Config{Aliases: map[string]*AliasConfig{}}
+	if filename == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", filename, err)
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]*AliasConfig{}
+	}
+	for name, a := range cfg.Aliases {
+		if a == nil {
+			return nil, fmt.Errorf("invalid config file %s: empty alias %s", filename, name)
+		}
+	}
+	return cfg, nil
+}
+
+// Save write config to file(only readable by owner, it contains secret keys)
+func (c *Config) Save(filename string) error {
+	if filename == "" {
+		return fmt.Errorf("unknown config file")
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(c); err != nil {
+		return err
+	}
+	data := buf.Bytes()
+	if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// Names return sorted alias names
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Aliases))
+	for name := range c.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SplitAlias split alias:bucket/key to alias and bucket/key,
+// arg without a configured alias prefix returns empty alias
+func (c *Config) SplitAlias(arg string) (string, string) {
+	i := strings.Index(arg, aliasSep)
+	if i < 1 {
+		return "", arg
+	}
+	name := arg[:i]
+	if _, ok := c.Aliases[name]; !ok {
+		return "", arg
+	}
+	return name, arg[i+1:]
+}
+
+// ApplyAlias apply alias settings to sc, settings specified by flags(changed) are kept
+func ApplyAlias(sc *S3Cli, a *AliasConfig, changed func(name string) bool) {
+	if a.Endpoint != "" && !changed("endpoint") {
+		sc.Endpoint = a.Endpoint
+	}
+	if !changed("ak") && !changed("sk") && !changed("profile") && (a.AccessKey != "" || a.Profile != "") {
+		sc.Profile = a.Profile
+		sc.AccessKey = a.AccessKey
+		sc.SecretKey = a.SecretKey
+		sc.SessionToken = a.SessionToken
+	}
+	if a.Region != "" && !changed("region") {
+		sc.Region = a.Region
+	}
+	if a.SignatureVersion != "" && !changed("v2sign") {
+		sc.V2Sign = strings.EqualFold(a.SignatureVersion, "v2")
+	}
+	if a.PathStyle != nil && !changed("path-style") {
+		sc.PathStyle = *a.PathStyle
+	}
+	if a.Proxy != "" && !changed("proxy") {
+		sc.Proxy = a.Proxy
+	}
+	if a.DialTimeout > 0 && !changed("dial-timeout") {
+		sc.DialTimeout = a.DialTimeout
+	}
+	if a.ResponseHeaderTimeout > 0 && !changed("response-header-timeout") {
+		sc.ResponseHeaderTimeout = a.ResponseHeaderTimeout
+	}
+	if a.Insecure != nil && !changed("insecure") {
+		sc.Insecure = *a.Insecure
+	}
+	if a.CABundle != "" && !changed("ca-bundle") {
+		sc.CABundle = a.CABundle
+	}
+	if a.ClientCert != "" && !changed("client-cert") && !changed("client-key") {
+		sc.ClientCert = a.ClientCert
+		sc.ClientKey = a.ClientKey
+	}
+	if a.TLSServerName != "" && !changed("tls-server-name") {
+		sc.TLSServerName = a.TLSServerName
+	}
+	if a.TLSMinVersion != "" && !changed("tls-min-version") {
+		sc.TLSMinVersion = a.TLSMinVersion
+	}
+}
+
+// maskSecret hide the secret except its first and last 2 chars
+func maskSecret(s string) string {
+	if len(s) <= 4 {
+		return strings.Repeat("*", len(s))
+	}
+	return s[:2] + strings.Repeat("*", len(s)-4) + s[len(s)-2:]
+}
+
+// AliasSet create or update an alias and save config file
+func (sc *S3Cli) AliasSet(filename, name string, a *AliasConfig) error {
+	if !aliasNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid alias name %s", name)
+	}
+	if err := a.validate(); err != nil {
+		return fmt.Errorf("invalid alias %s: %w", name, err)
+	}
+	cfg, err := LoadConfig(filename)
+	if err != nil {
+		return err
+	}
+	cfg.Aliases[name] = a
+	if err := cfg.Save(filename); err != nil {
+		return fmt.Errorf("save config failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintf(sc.out(), "alias %s saved to %s\n", name, filename)
+	}
+	return nil
+}
+
+// AliasList list all aliases(secret keys are masked)
+func (sc *S3Cli) AliasList(filename string) (map[string]AliasConfig, error) {
+	cfg, err := LoadConfig(filename)
+	if err != nil {
+		return nil, err
+	}
+	aliases := make(map[string]AliasConfig, len(cfg.Aliases))
+	for name, a := range cfg.Aliases {
+		v := *a
+		v.SecretKey = maskSecret(v.SecretKey)
+		v.SessionToken = maskSecret(v.SessionToken)
+		aliases[name] = v
+	}
+	return aliases, nil
+}
+
+// AliasRemove remove aliases and save config file
+func (sc *S3Cli) AliasRemove(filename string, names []string) error {
+	cfg, err := LoadConfig(filename)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, ok := cfg.Aliases[name]; !ok {
+			return fmt.Errorf("alias %s not found", name)
+		}
+		delete(cfg.Aliases, name)
+	}
+	if err := cfg.Save(filename); err != nil {
+		return fmt.Errorf("save config failed: %w", err)
+	}
+	return nil
+}
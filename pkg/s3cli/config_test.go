@@ -0,0 +1,97 @@
+package s3cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_loadConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "s3cli", "config.yaml")
+	cfg, err := LoadConfig(filename)
+	if err != nil || len(cfg.Aliases) != 0 {
+		t.Fatalf("LoadConfig not exist file expect empty config, got %v, %v", cfg, err)
+	}
+
+	pathStyle := false
+	cfg.Aliases["minio"] = &AliasConfig{
+		Endpoint:         "http://127.0.0.1:9000",
+		AccessKey:        "ak",
+		SecretKey:        "sk",
+		SignatureVersion: "v2",
+		PathStyle:        &pathStyle,
+		DialTimeout:      5,
+	}
+	if err := cfg.Save(filename); err != nil {
+		t.Fatal("save config failed: ", err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("config file mode expect 0600, got %v", info.Mode().Perm())
+	}
+
+	cfg, err = LoadConfig(filename)
+	if err != nil {
+		t.Fatal("LoadConfig failed: ", err)
+	}
+	a, ok := cfg.Aliases["minio"]
+	if !ok || a.Endpoint != "http://127.0.0.1:9000" || a.SecretKey != "sk" || a.PathStyle == nil || *a.PathStyle || a.DialTimeout != 5 {
+		t.Errorf("LoadConfig alias minio mismatch: %+v", a)
+	}
+
+	if err := os.WriteFile(filename, []byte("aliases: [1, 2"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(filename); err == nil {
+		t.Errorf("LoadConfig invalid yaml expect error")
+	}
+}
+
+func Test_splitAlias(t *testing.T) {
+	cfg := &Config{Aliases: map[string]*AliasConfig{"minio": {Endpoint: "http://minio:9000"}}}
+	cases := map[string][2]string{
+		"minio:bucket/key": {"minio", "bucket/key"},
+		"minio:":           {"minio", ""},
+		"other:bucket/key": {"", "other:bucket/key"},
+		"bucket/a:b":       {"", "bucket/a:b"},
+		":bucket":          {"", ":bucket"},
+	}
+	for k, v := range cases {
+		name, rest := cfg.SplitAlias(k)
+		if name != v[0] || rest != v[1] {
+			t.Errorf("splitAlias(%s) expect: %v, got: %s, %s", k, v, name, rest)
+		}
+	}
+}
+
+func Test_applyAlias(t *testing.T) {
+	pathStyle := false
+	a := &AliasConfig{
+		Endpoint:         "http://minio:9000",
+		AccessKey:        "alias-ak",
+		SecretKey:        "alias-sk",
+		Region:           "us-east-1",
+		SignatureVersion: "v2",
+		PathStyle:        &pathStyle,
+		Proxy:            "none",
+	}
+	sc := &S3Cli{Endpoint: "http://flag:9020", AccessKey: "ak", SecretKey: "sk", Region: "cn-north-1", PathStyle: true}
+	changed := map[string]bool{"endpoint": true}
+	ApplyAlias(sc, a, func(name string) bool { return changed[name] })
+	if sc.Endpoint != "http://flag:9020" {
+		t.Errorf("flag endpoint should be kept, got %s", sc.Endpoint)
+	}
+	if sc.AccessKey != "alias-ak" || sc.SecretKey != "alias-sk" || sc.Region != "us-east-1" || !sc.V2Sign || sc.PathStyle || sc.Proxy != "none" {
+		t.Errorf("alias settings not applied: %+v", sc)
+	}
+
+	if err := (&AliasConfig{Endpoint: "http://h", SignatureVersion: "v3"}).validate(); err == nil {
+		t.Errorf("invalid signatureVersion expect error")
+	}
+	if err := (&AliasConfig{}).validate(); err == nil {
+		t.Errorf("empty endpoint expect error")
+	}
+}
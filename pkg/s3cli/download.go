@@ -0,0 +1,259 @@
+package s3cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	// partFileSuffix is the suffix of a partial downloaded file
+	partFileSuffix = ".part"
+	// stateFileSuffix is the suffix of a partial downloaded file's sidecar state
+	stateFileSuffix = ".part.state"
+)
+
+// errObjectChanged means the Object ETag changed during a ranged download
+var errObjectChanged = errors.New("object changed during download")
+
+// downloadState is the sidecar state file of a ranged download,
+// it records the completed parts so an interrupted download can resume
+type downloadState struct {
+	ETag     string  `json:"etag"`
+	Size     int64   `json:"size"`
+	PartSize int64   `json:"partSize"`
+	Done     []int64 `json:"done"` // completed part numbers(from 0)
+}
+
+// loadDownloadState read a state file, a not exist state file returns nil
+func loadDownloadState(filename string) (*downloadState, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	state := &downloadState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid state file %s: %w", filename, err)
+	}
+	return state, nil
+}
+
+// save write state to file
+func (s *downloadState) save(filename string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := filename + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// offsetWriterAt write to w at a fixed offset
+type offsetWriterAt struct {
+	w   io.WriterAt
+	off int64
+}
+
+func (o offsetWriterAt) WriteAt(p []byte, pos int64) (int, error) {
+	return o.w.WriteAt(p, o.off+pos)
+}
+
+// GetResult record a downloaded Object of GetObject and GetObjectRanged,
+// Parts and ResumedParts are only set by GetObjectRanged
+type GetResult struct {
+	Key          string `json:"key"`
+	File         string `json:"file"`
+	Size         int64  `json:"size"`
+	ETag         string `json:"etag"`
+	VersionID    string `json:"versionId,omitempty"`
+	Parts        int64  `json:"parts,omitempty"`
+	ResumedParts int    `json:"resumedParts,omitempty"`
+}
+
+// GetObjectRanged download a Object with concurrent ranged GETs(s3manager.Downloader),
+// ranges are written into filename.part and completed ranges are recorded in
+// filename.part.state, a interrupted download resumes from the completed ranges
+// unless the Object ETag changed or filename.part is removed(or size mismatch)
+func (sc *S3Cli) GetObjectRanged(ctx context.Context, bucket, key, version, filename string, partSize int64, concurrency int, overwrite bool) (*GetResult, error) {
+	if _, err := os.Stat(filename); err == nil && !overwrite {
+		return nil, fmt.Errorf("local file %s already exists", filename)
+	}
+	if partSize < 1 {
+		partSize = s3manager.DefaultDownloadPartSize
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var versionID *string
+	if version != "" {
+		versionID = aws.String(version)
+	}
+	head, err := sc.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: versionID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("head object %s failed: %w", key, err)
+	}
+	size := aws.Int64Value(head.ContentLength)
+	etag := aws.StringValue(head.ETag)
+
+	partFile := filename + partFileSuffix
+	stateFile := filename + stateFileSuffix
+	state, err := loadDownloadState(stateFile)
+	if err != nil {
+		return nil, err
+	}
+	// diagnostics go to stderr, stdout may be the JSON result
+	if state != nil && (state.ETag != etag || state.Size != size) {
+		if sc.verboseOutput() {
+			fmt.Fprintf(os.Stderr, "Object %s changed(ETag %s -> %s), restart download\n", key, state.ETag, etag)
+		}
+		state = nil
+		os.Remove(partFile)
+		os.Remove(stateFile)
+	}
+	if state != nil {
+		// completed ranges are lost with a removed or truncated part file
+		if info, err := os.Stat(partFile); err != nil || info.Size() != size {
+			if sc.verboseOutput() {
+				fmt.Fprintf(os.Stderr, "%s missing or size mismatch, restart download\n", partFile)
+			}
+			state = nil
+			os.Remove(partFile)
+			os.Remove(stateFile)
+		}
+	}
+	if state == nil {
+		state = &downloadState{ETag: etag, Size: size, PartSize: partSize}
+	}
+	partSize = state.PartSize
+
+	fd, err := os.OpenFile(partFile, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	if err := fd.Truncate(size); err != nil {
+		return nil, err
+	}
+
+	partNum := (size + partSize - 1) / partSize
+	done := make(map[int64]bool, len(state.Done))
+	for _, n := range state.Done {
+		done[n] = true
+	}
+	resumed := len(done)
+
+	downloader := s3manager.NewDownloaderWithClient(sc.Client, func(d *s3manager.Downloader) {
+		d.Concurrency = 1
+		d.PartSize = partSize
+	})
+	mu := sync.Mutex{}
+	parts := make(chan int64)
+	errs := make(chan error, partNum)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range parts {
+				start := n * partSize
+				end := start + partSize - 1
+				if end >= size {
+					end = size - 1
+				}
+				_, err := downloader.DownloadWithContext(ctx, offsetWriterAt{w: fd, off: start}, &s3.GetObjectInput{
+					Bucket:    aws.String(bucket),
+					Key:       aws.String(key),
+					VersionId: versionID,
+					IfMatch:   aws.String(etag),
+					Range:     aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+				})
+				if err != nil {
+					if e, ok := err.(awserr.RequestFailure); ok && e.StatusCode() == http.StatusPreconditionFailed {
+						err = errObjectChanged
+					}
+					errs <- fmt.Errorf("download part %d failed: %w", n, err)
+					continue
+				}
+				mu.Lock()
+				state.Done = append(state.Done, n)
+				err = state.save(stateFile)
+				mu.Unlock()
+				if err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	for n := int64(0); n < partNum && ctx.Err() == nil && len(errs) == 0; n++ {
+		if !done[n] {
+			parts <- n
+		}
+	}
+	close(parts)
+	wg.Wait()
+	close(errs)
+
+	// the first error, or the Object changed error of any part
+	for e := range errs {
+		if err == nil || errors.Is(e, errObjectChanged) {
+			err = e
+		}
+	}
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		if errors.Is(err, errObjectChanged) {
+			// the completed ranges are invalid
+			fd.Close()
+			os.Remove(partFile)
+			os.Remove(stateFile)
+		}
+		return nil, err
+	}
+
+	if err := fd.Close(); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(partFile, filename); err != nil {
+		return nil, err
+	}
+	os.Remove(stateFile)
+	if head.LastModified != nil {
+		if err := os.Chtimes(filename, time.Now(), *head.LastModified); err != nil {
+			return nil, err
+		}
+	}
+
+	return &GetResult{
+		Key:          key,
+		File:         filename,
+		Size:         size,
+		ETag:         etag,
+		VersionID:    aws.StringValue(head.VersionId),
+		Parts:        partNum,
+		ResumedParts: resumed,
+	}, nil
+}
@@ -0,0 +1,100 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_getObjectRanged(t *testing.T) {
+	key := "keyToTestGetObjectRanged"
+	content := bytes.Repeat([]byte("0123456789"), 10)
+	_, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("getObjectRanged backend PutObject failed: %s", err)
+	}
+	filename := filepath.Join(t.TempDir(), key)
+	if _, err := s3cliTest.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 16, 3, false); err != nil {
+		t.Fatalf("getObjectRanged failed: %s", err)
+	}
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, content) {
+		t.Errorf("getObjectRanged expect: %s, got: %s", content, data)
+	}
+	if _, err := os.Stat(filename + stateFileSuffix); err == nil {
+		t.Errorf("getObjectRanged state file should be removed")
+	}
+	if _, err := s3cliTest.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 16, 3, false); err == nil {
+		t.Errorf("getObjectRanged should not overwrite local file")
+	}
+}
+
+func Test_getObjectRangedResume(t *testing.T) {
+	key := "keyToTestGetObjectRangedResume"
+	content := bytes.Repeat([]byte("abcdefghij"), 5)
+	_, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		t.Fatalf("getObjectRanged backend PutObject failed: %s", err)
+	}
+	head, err := s3cliTest.Client.HeadObject(&s3.HeadObjectInput{Bucket: &testBucketName, Key: &key})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// part 0 and part 2 completed, but filled with a marker to detect re-download
+	filename := filepath.Join(t.TempDir(), key)
+	partial := make([]byte, len(content))
+	copy(partial, content)
+	copy(partial[0:10], bytes.Repeat([]byte("X"), 10))
+	copy(partial[20:30], bytes.Repeat([]byte("X"), 10))
+	if err := os.WriteFile(filename+partFileSuffix, partial, 0644); err != nil {
+		t.Fatal(err)
+	}
+	state := &downloadState{ETag: *head.ETag, Size: int64(len(content)), PartSize: 10, Done: []int64{0, 2}}
+	if err := state.save(filename + stateFileSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s3cliTest.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 16, 2, false); err != nil {
+		t.Fatalf("getObjectRanged resume failed: %s", err)
+	}
+	data, _ := os.ReadFile(filename)
+	if !bytes.Equal(data, partial) {
+		t.Errorf("getObjectRanged resume expect: %s, got: %s", partial, data)
+	}
+
+	// ETag changed, the partial file is discarded
+	if err := os.WriteFile(filename+partFileSuffix, partial, 0644); err != nil {
+		t.Fatal(err)
+	}
+	state.ETag = "\"changed\""
+	if err := state.save(filename + stateFileSuffix); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s3cliTest.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 16, 2, true); err != nil {
+		t.Fatalf("getObjectRanged restart failed: %s", err)
+	}
+	data, _ = os.ReadFile(filename)
+	if !bytes.Equal(data, content) {
+		t.Errorf("getObjectRanged restart expect: %s, got: %s", content, data)
+	}
+	// part file removed between runs, the state is discarded
+	state.ETag = *head.ETag
+	if err := state.save(filename + stateFileSuffix); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filename + partFileSuffix)
+	if _, err := s3cliTest.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 16, 2, true); err != nil {
+		t.Fatalf("getObjectRanged without part file failed: %s", err)
+	}
+	data, _ = os.ReadFile(filename)
+	if !bytes.Equal(data, content) {
+		t.Errorf("getObjectRanged without part file expect: %s, got: %s", content, data)
+	}
+}
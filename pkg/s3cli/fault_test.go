@@ -0,0 +1,348 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+)
+
+// fault is a failure injected into a request
+type fault int
+
+const (
+	faultThrottle     fault = iota + 1 // 503 SlowDown
+	faultServerError                   // 500 InternalError
+	faultTruncate                      // response body cut in the middle
+	faultPrecondition                  // 412 PreconditionFailed(If-Match mismatch)
+)
+
+// faultClient is a s3iface.S3API that injects queued faults into requests,
+// faults of an operation are consumed one per request, then requests pass through
+type faultClient struct {
+	s3iface.S3API
+
+	mu     sync.Mutex
+	faults map[string][]fault
+}
+
+func newFaultClient(client s3iface.S3API) *faultClient {
+	return &faultClient{S3API: client, faults: map[string][]fault{}}
+}
+
+// add queue faults of operation(e.g. GetObject)
+func (c *faultClient) add(operation string, faults ...fault) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.faults[operation] = append(c.faults[operation], faults...)
+}
+
+// next pop the next fault of operation, 0 if none
+func (c *faultClient) next(operation string) fault {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	q := c.faults[operation]
+	if len(q) == 0 {
+		return 0
+	}
+	c.faults[operation] = q[1:]
+	return q[0]
+}
+
+// inject install the next fault of the request's operation
+func (c *faultClient) inject(req *request.Request) {
+	switch c.next(req.Operation.Name) {
+	case faultThrottle:
+		req.Handlers.Send.Clear()
+		req.Handlers.Send.PushBack(errorResponse(http.StatusServiceUnavailable, "SlowDown", "Please reduce your request rate."))
+	case faultServerError:
+		req.Handlers.Send.Clear()
+		req.Handlers.Send.PushBack(errorResponse(http.StatusInternalServerError, "InternalError", "We encountered an internal error. Please try again."))
+	case faultPrecondition:
+		req.Handlers.Send.Clear()
+		req.Handlers.Send.PushBack(errorResponse(http.StatusPreconditionFailed, "PreconditionFailed", "At least one of the pre-conditions you specified did not hold"))
+	case faultTruncate:
+		req.Handlers.Send.PushBack(func(r *request.Request) {
+			if r.Error != nil || r.HTTPResponse == nil {
+				return
+			}
+			n := r.HTTPResponse.ContentLength / 2
+			r.HTTPResponse.Body = &truncatedBody{r: io.LimitReader(r.HTTPResponse.Body, n), c: r.HTTPResponse.Body}
+		})
+	}
+}
+
+// errorResponse reply a S3 error instead of sending the request
+func errorResponse(status int, code, message string) func(*request.Request) {
+	return func(r *request.Request) {
+		body := `<?xml version="1.0" encoding="UTF-8"?><Error><Code>` + code + `</Code><Message>` + message + `</Message></Error>`
+		r.HTTPResponse = &http.Response{
+			Status:        http.StatusText(status),
+			StatusCode:    status,
+			Header:        http.Header{"X-Amz-Request-Id": []string{"fault"}},
+			Body:          io.NopCloser(strings.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       r.HTTPRequest,
+		}
+	}
+}
+
+// truncatedBody fail with io.ErrUnexpectedEOF like a connection closed early
+type truncatedBody struct {
+	r io.Reader
+	c io.Closer
+}
+
+func (b *truncatedBody) Read(p []byte) (int, error) {
+	n, err := b.r.Read(p)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+func (b *truncatedBody) Close() error {
+	return b.c.Close()
+}
+
+func (c *faultClient) ListObjectsRequest(in *s3.ListObjectsInput) (*request.Request, *s3.ListObjectsOutput) {
+	req, out := c.S3API.ListObjectsRequest(in)
+	c.inject(req)
+	return req, out
+}
+
+func (c *faultClient) DeleteObjectsRequest(in *s3.DeleteObjectsInput) (*request.Request, *s3.DeleteObjectsOutput) {
+	req, out := c.S3API.DeleteObjectsRequest(in)
+	c.inject(req)
+	return req, out
+}
+
+func (c *faultClient) UploadPartRequest(in *s3.UploadPartInput) (*request.Request, *s3.UploadPartOutput) {
+	req, out := c.S3API.UploadPartRequest(in)
+	c.inject(req)
+	return req, out
+}
+
+// UploadPartCopyWithContext emulate UploadPartCopy(not supported by gofakes3) with a ranged GetObject and UploadPart
+func (c *faultClient) UploadPartCopyWithContext(ctx aws.Context, in *s3.UploadPartCopyInput, opts ...request.Option) (*s3.UploadPartCopyOutput, error) {
+	source, err := url.PathUnescape(aws.StringValue(in.CopySource))
+	if err != nil {
+		return nil, err
+	}
+	bucket, key := (&S3Cli{}).SplitKeyValue(source, "/")
+	obj, err := c.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Range:  in.CopySourceRange,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Body.Close()
+	body, err := io.ReadAll(obj.Body)
+	if err != nil {
+		return nil, err
+	}
+	out, err := c.UploadPartWithContext(ctx, &s3.UploadPartInput{
+		Body:       bytes.NewReader(body),
+		Bucket:     in.Bucket,
+		Key:        in.Key,
+		PartNumber: in.PartNumber,
+		UploadId:   in.UploadId,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &s3.UploadPartCopyOutput{CopyPartResult: &s3.CopyPartResult{ETag: out.ETag}}, nil
+}
+
+func (c *faultClient) GetObjectRequest(in *s3.GetObjectInput) (*request.Request, *s3.GetObjectOutput) {
+	req, out := c.S3API.GetObjectRequest(in)
+	c.inject(req)
+	return req, out
+}
+
+// GetObjectWithContext send the GetObject request of c(with faults), s3manager.Downloader use it
+func (c *faultClient) GetObjectWithContext(ctx aws.Context, in *s3.GetObjectInput, opts ...request.Option) (*s3.GetObjectOutput, error) {
+	req, out := c.GetObjectRequest(in)
+	req.SetContext(ctx)
+	req.ApplyOptions(opts...)
+	return out, req.Send()
+}
+
+func Test_deletePrefixFault(t *testing.T) {
+	bucket := "bucket-fault"
+	if err := s3Backend.CreateBucket(bucket); err != nil {
+		t.Fatal("backend CreateBucket failed: ", err)
+	}
+	for _, k := range []string{"del/a", "del/b"} {
+		if _, err := s3Backend.PutObject(bucket, k, nil, strings.NewReader(k), int64(len(k))); err != nil {
+			t.Fatal("backend PutObject failed: ", err)
+		}
+	}
+	fc := newFaultClient(s3cliTest.Client)
+	sc := s3cliTest
+	sc.Client = fc
+	sc.Writer = io.Discard
+
+	for _, c := range []struct {
+		operation string
+		fault     fault
+	}{
+		{"ListObjects", faultThrottle},
+		{"ListObjects", faultServerError},
+		{"ListObjects", faultTruncate},
+		{"DeleteObjects", faultServerError},
+	} {
+		fc.add(c.operation, c.fault)
+		if _, err := sc.DeletePrefix(context.Background(), bucket, "del/", false); err == nil {
+			t.Errorf("deletePrefix with %s fault %d expect error", c.operation, c.fault)
+		}
+		for _, k := range []string{"del/a", "del/b"} {
+			if _, err := s3Backend.HeadObject(bucket, k); err != nil {
+				t.Errorf("%s fault %d should not delete %s", c.operation, c.fault, k)
+			}
+		}
+	}
+
+	if _, err := sc.DeletePrefix(context.Background(), bucket, "del/", false); err != nil {
+		t.Fatal("deletePrefix failed: ", err)
+	}
+	if _, err := s3Backend.HeadObject(bucket, "del/a"); err == nil {
+		t.Errorf("deletePrefix should delete del/a")
+	}
+}
+
+func Test_mpuUploadFault(t *testing.T) {
+	ctx := context.Background()
+	key := "mpu-fault"
+	cmo, err := s3cliTest.Client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(testBucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		t.Fatal("CreateMultipartUpload failed: ", err)
+	}
+	uid := aws.StringValue(cmo.UploadId)
+	dir := t.TempDir()
+	files := map[int64]string{}
+	for n := int64(1); n <= 3; n++ {
+		files[n] = filepath.Join(dir, fmt.Sprintf("%s.%d", key, n))
+		if err := os.WriteFile(files[n], []byte(files[n]), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fc := newFaultClient(s3cliTest.Client)
+	sc := s3cliTest
+	sc.Client = fc
+	sc.Writer = io.Discard
+
+	fc.add("UploadPart", faultThrottle, faultServerError)
+	_, err = sc.MPUUpload(ctx, testBucketName, key, uid, files)
+	if err == nil || err.Error() != "2 of 3 parts upload failed" {
+		t.Errorf("mpuUpload expect 2 of 3 parts failed, got %v", err)
+	}
+
+	files[4] = filepath.Join(dir, "not-exist")
+	if _, err := sc.MPUUpload(ctx, testBucketName, key, uid, files); err == nil || err.Error() != "1 of 4 parts upload failed" {
+		t.Errorf("mpuUpload missing file expect 1 of 4 parts failed, got %v", err)
+	}
+	delete(files, 4)
+	if _, err := sc.MPUUpload(ctx, testBucketName, key, uid, files); err != nil {
+		t.Errorf("mpuUpload failed: %s", err)
+	}
+}
+
+func Test_getObjectFault(t *testing.T) {
+	key := "get-fault"
+	content := []byte("0123456789abcdefghij")
+	if _, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal("backend PutObject failed: ", err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	fc := newFaultClient(s3cliTest.Client)
+	sc := s3cliTest
+	sc.Client = fc
+	sc.Writer = io.Discard
+
+	for _, f := range []fault{faultThrottle, faultServerError, faultTruncate} {
+		fc.add("GetObject", f)
+		if _, err := sc.GetObject(context.Background(), testBucketName, key, "", "", true); err == nil {
+			t.Errorf("getObject with fault %d expect error", f)
+		}
+		if _, err := os.Stat(key); err == nil {
+			t.Errorf("getObject with fault %d should not leave local file", f)
+		}
+	}
+
+	if _, err := sc.GetObject(context.Background(), testBucketName, key, "", "", false); err != nil {
+		t.Fatal("getObject failed: ", err)
+	}
+	data, err := os.ReadFile(key)
+	if err != nil || string(data) != string(content) {
+		t.Errorf("getObject expect %s, got %s, %v", content, data, err)
+	}
+}
+
+func Test_getObjectRangedFault(t *testing.T) {
+	key := "get-ranged-fault"
+	content := bytes.Repeat([]byte("0123456789"), 4)
+	if _, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatal("backend PutObject failed: ", err)
+	}
+	fc := newFaultClient(s3cliTest.Client)
+	sc := s3cliTest
+	sc.Client = fc
+	buf := &bytes.Buffer{}
+	sc.Writer = buf
+	sc.Output = OutputJSON
+	filename := filepath.Join(t.TempDir(), key)
+
+	// Object changed(If-Match mismatch) during download, the part and state files are removed
+	fc.add("GetObject", 0, faultPrecondition)
+	if _, err := sc.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 10, 1, false); !errors.Is(err, errObjectChanged) {
+		t.Fatalf("getObjectRanged expect %s, got %v", errObjectChanged, err)
+	}
+	for _, name := range []string{filename + partFileSuffix, filename + stateFileSuffix} {
+		if _, err := os.Stat(name); err == nil {
+			t.Errorf("getObjectRanged Object changed should remove %s", name)
+		}
+	}
+
+	// a stale state file is discarded, its diagnostic not mixed into the JSON result
+	state := &downloadState{ETag: "\"stale\"", Size: int64(len(content)), PartSize: 10, Done: []int64{0}}
+	if err := state.save(filename + stateFileSuffix); err != nil {
+		t.Fatal(err)
+	}
+	sc.Output = OutputVerbose
+	if _, err := sc.GetObjectRanged(context.Background(), testBucketName, key, "", filename, 10, 2, false); err != nil {
+		t.Fatal("getObjectRanged restart failed: ", err)
+	}
+	if strings.Contains(buf.String(), "restart download") {
+		t.Errorf("getObjectRanged diagnostics expect on stderr, got %q", buf.String())
+	}
+	if data, err := os.ReadFile(filename); err != nil || !bytes.Equal(data, content) {
+		t.Errorf("getObjectRanged restart expect %s, got %s, %v", content, data, err)
+	}
+}
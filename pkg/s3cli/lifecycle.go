@@ -0,0 +1,366 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// maxLifecycleRules is the max number of rules of a Bucket lifecycle configuration
+const maxLifecycleRules = 1000
+
+var lifecycleSchema = schema{
+	columns: []string{"ID", "Status", "Filter", "Actions"},
+	simple:  []string{"ID", "Status", "Actions"},
+}
+
+// LifecycleRuleOptions is a lifecycle rule of the common actions(command flags),
+// a zero days action is not added
+type LifecycleRuleOptions struct {
+	ID                       string
+	Prefix                   string
+	ExpireDays               int64 // expire current versions N days after creation
+	AbortMPUDays             int64 // abort incomplete MPU N days after initiation
+	NoncurrentTransitionDays int64 // transition noncurrent versions N days after they become noncurrent
+	NoncurrentStorageClass   string
+}
+
+// Rule create the lifecycle rule of o
+func (o LifecycleRuleOptions) Rule() *s3.LifecycleRule {
+	r := &s3.LifecycleRule{
+		ID:     aws.String(o.ID),
+		Status: aws.String(s3.ExpirationStatusEnabled),
+		Filter: &s3.LifecycleRuleFilter{Prefix: aws.String(o.Prefix)},
+	}
+	if o.ExpireDays != 0 {
+		r.Expiration = &s3.LifecycleExpiration{Days: aws.Int64(o.ExpireDays)}
+	}
+	if o.AbortMPUDays != 0 {
+		r.AbortIncompleteMultipartUpload = &s3.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int64(o.AbortMPUDays)}
+	}
+	if o.NoncurrentTransitionDays != 0 {
+		r.NoncurrentVersionTransitions = []*s3.NoncurrentVersionTransition{{
+			NoncurrentDays: aws.Int64(o.NoncurrentTransitionDays),
+			StorageClass:   aws.String(o.NoncurrentStorageClass),
+		}}
+	}
+	return r
+}
+
+// LoadLifecycleConfig load the lifecycle configuration(JSON or YAML) of file,
+// rules without Filter and Prefix apply to all Objects
+func LoadLifecycleConfig(filename string) (*s3.BucketLifecycleConfiguration, error) {
+	cfg := &s3.BucketLifecycleConfiguration{}
+	if err := decodeFile(filename, cfg); err != nil {
+		return nil, fmt.Errorf("load lifecycle %s failed: %w", filename, err)
+	}
+	for _, r := range cfg.Rules {
+		if r != nil && r.Filter == nil && r.Prefix == nil {
+			r.Filter = &s3.LifecycleRuleFilter{Prefix: aws.String("")}
+		}
+	}
+	return cfg, nil
+}
+
+// validLifecycleDays check the days of a lifecycle action
+func validLifecycleDays(rule, action string, days *int64) error {
+	if days == nil || *days <= 0 {
+		return fmt.Errorf("rule %s: %s days must be positive", rule, action)
+	}
+	return nil
+}
+
+// validTransitionStorageClass check the storage class of a lifecycle transition
+func validTransitionStorageClass(rule string, class *string) error {
+	for _, v := range s3.TransitionStorageClass_Values() {
+		if aws.StringValue(class) == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("rule %s: invalid transition storage class %q(%s)", rule, aws.StringValue(class), strings.Join(s3.TransitionStorageClass_Values(), ", "))
+}
+
+// ruleNames check the n(at most max) rules of a kind of configuration are not empty
+// and have unique IDs, and return the names(ID or #N) of the rules,
+// id return the ID of rule i and false if the rule is empty
+func ruleNames(kind string, n, max int, id func(i int) (*string, bool)) ([]string, error) {
+	if n == 0 {
+		return nil, fmt.Errorf("no %s rule", kind)
+	}
+	if n > max {
+		return nil, fmt.Errorf("%d %s rules, at most %d", n, kind, max)
+	}
+	names := make([]string, n)
+	ids := map[string]bool{}
+	for i := range names {
+		v, ok := id(i)
+		if !ok {
+			return nil, fmt.Errorf("rule #%d is empty", i+1)
+		}
+		name := aws.StringValue(v)
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		} else if len(name) > 255 {
+			return nil, fmt.Errorf("rule %s: ID longer than 255 characters", name)
+		} else if ids[name] {
+			return nil, fmt.Errorf("duplicate rule ID %s", name)
+		}
+		ids[name] = true
+		names[i] = name
+	}
+	return names, nil
+}
+
+// filterItems format the prefix and tags of a rule filter
+func filterItems(prefix *string, tags []*s3.Tag) []string {
+	items := []string{}
+	if prefix != nil {
+		items = append(items, "prefix="+aws.StringValue(prefix))
+	}
+	for _, t := range tags {
+		if t != nil {
+			items = append(items, fmt.Sprintf("tag=%s:%s", aws.StringValue(t.Key), aws.StringValue(t.Value)))
+		}
+	}
+	return items
+}
+
+// validateLifecycle check a lifecycle configuration before put it
+func validateLifecycle(cfg *s3.BucketLifecycleConfiguration) error {
+	if cfg == nil {
+		return errors.New("no lifecycle rule")
+	}
+	names, err := ruleNames("lifecycle", len(cfg.Rules), maxLifecycleRules, func(i int) (*string, bool) {
+		if r := cfg.Rules[i]; r != nil {
+			return r.ID, true
+		}
+		return nil, false
+	})
+	if err != nil {
+		return err
+	}
+	for i, r := range cfg.Rules {
+		name := names[i]
+		switch aws.StringValue(r.Status) {
+		case s3.ExpirationStatusEnabled, s3.ExpirationStatusDisabled:
+		default:
+			return fmt.Errorf("rule %s: invalid Status %q(Enabled or Disabled)", name, aws.StringValue(r.Status))
+		}
+		if r.Prefix != nil && r.Filter != nil {
+			return fmt.Errorf("rule %s: Prefix and Filter are exclusive", name)
+		}
+		if r.Expiration == nil && r.AbortIncompleteMultipartUpload == nil && r.NoncurrentVersionExpiration == nil &&
+			len(r.Transitions) == 0 && len(r.NoncurrentVersionTransitions) == 0 {
+			return fmt.Errorf("rule %s: no action", name)
+		}
+
+		if e := r.Expiration; e != nil {
+			n := 0
+			if e.Days != nil {
+				n++
+				if err := validLifecycleDays(name, "expiration", e.Days); err != nil {
+					return err
+				}
+			}
+			if e.Date != nil {
+				n++
+			}
+			if e.ExpiredObjectDeleteMarker != nil {
+				n++
+			}
+			if n != 1 {
+				return fmt.Errorf("rule %s: expiration requires one of Days, Date and ExpiredObjectDeleteMarker", name)
+			}
+		}
+		if a := r.AbortIncompleteMultipartUpload; a != nil {
+			if err := validLifecycleDays(name, "abort incomplete MPU", a.DaysAfterInitiation); err != nil {
+				return err
+			}
+		}
+		if e := r.NoncurrentVersionExpiration; e != nil {
+			if err := validLifecycleDays(name, "noncurrent expiration", e.NoncurrentDays); err != nil {
+				return err
+			}
+		}
+		for _, t := range r.Transitions {
+			if t == nil {
+				return fmt.Errorf("rule %s: empty transition", name)
+			}
+			if (t.Days == nil) == (t.Date == nil) {
+				return fmt.Errorf("rule %s: transition requires one of Days and Date", name)
+			}
+			if aws.Int64Value(t.Days) < 0 {
+				return fmt.Errorf("rule %s: transition days must not be negative", name)
+			}
+			if err := validTransitionStorageClass(name, t.StorageClass); err != nil {
+				return err
+			}
+		}
+		for _, t := range r.NoncurrentVersionTransitions {
+			if t == nil {
+				return fmt.Errorf("rule %s: empty transition", name)
+			}
+			if err := validLifecycleDays(name, "noncurrent transition", t.NoncurrentDays); err != nil {
+				return err
+			}
+			if err := validTransitionStorageClass(name, t.StorageClass); err != nil {
+				return err
+			}
+		}
+	}
+	return cfg.Validate()
+}
+
+// lifecycleFilter format the Objects filter of a lifecycle rule
+func lifecycleFilter(r *s3.LifecycleRule) string {
+	if r.Prefix != nil {
+		return strings.Join(filterItems(r.Prefix, nil), ",")
+	}
+	f := r.Filter
+	if f == nil {
+		return ""
+	}
+	prefix, tags := f.Prefix, []*s3.Tag{f.Tag}
+	gt, lt := f.ObjectSizeGreaterThan, f.ObjectSizeLessThan
+	if f.And != nil {
+		prefix, tags = f.And.Prefix, f.And.Tags
+		gt, lt = f.And.ObjectSizeGreaterThan, f.And.ObjectSizeLessThan
+	}
+	items := filterItems(prefix, tags)
+	if gt != nil {
+		items = append(items, fmt.Sprintf("size>%d", *gt))
+	}
+	if lt != nil {
+		items = append(items, fmt.Sprintf("size<%d", *lt))
+	}
+	return strings.Join(items, ",")
+}
+
+// lifecycleActions format the actions of a lifecycle rule
+func lifecycleActions(r *s3.LifecycleRule) string {
+	items := []string{}
+	if e := r.Expiration; e != nil {
+		switch {
+		case e.Days != nil:
+			items = append(items, fmt.Sprintf("expire=%dd", *e.Days))
+		case e.Date != nil:
+			items = append(items, "expire="+formatValue(e.Date))
+		case aws.BoolValue(e.ExpiredObjectDeleteMarker):
+			items = append(items, "expire-delete-marker")
+		}
+	}
+	for _, t := range r.Transitions {
+		if t.Date != nil {
+			items = append(items, fmt.Sprintf("transition=%s:%s", formatValue(t.Date), aws.StringValue(t.StorageClass)))
+		} else {
+			items = append(items, fmt.Sprintf("transition=%dd:%s", aws.Int64Value(t.Days), aws.StringValue(t.StorageClass)))
+		}
+	}
+	for _, t := range r.NoncurrentVersionTransitions {
+		items = append(items, fmt.Sprintf("noncurrent-transition=%dd:%s", aws.Int64Value(t.NoncurrentDays), aws.StringValue(t.StorageClass)))
+	}
+	if e := r.NoncurrentVersionExpiration; e != nil {
+		items = append(items, fmt.Sprintf("noncurrent-expire=%dd", aws.Int64Value(e.NoncurrentDays)))
+	}
+	if a := r.AbortIncompleteMultipartUpload; a != nil {
+		items = append(items, fmt.Sprintf("abort-mpu=%dd", aws.Int64Value(a.DaysAfterInitiation)))
+	}
+	return strings.Join(items, ",")
+}
+
+// printLifecycleRules print lifecycle rules as records
+func (sc *S3Cli) printLifecycleRules(rules []*s3.LifecycleRule) error {
+	p := sc.newPrinter(lifecycleSchema)
+	for _, r := range rules {
+		if err := p.add(aws.StringValue(r.ID), aws.StringValue(r.Status), lifecycleFilter(r), lifecycleActions(r)); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// BucketLifecycleGet get a Bucket's lifecycle rules
+func (sc *S3Cli) BucketLifecycleGet(ctx context.Context, bucket string) (*s3.GetBucketLifecycleConfigurationOutput, error) {
+	req, resp := sc.Client.GetBucketLifecycleConfigurationRequest(&s3.GetBucketLifecycleConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get lifecycle failed: %w", err)
+	}
+	return resp, nil
+}
+
+// BucketLifecyclePut validate and put(replace) a Bucket's lifecycle rules
+func (sc *S3Cli) BucketLifecyclePut(ctx context.Context, bucket string, cfg *s3.BucketLifecycleConfiguration) error {
+	if err := validateLifecycle(cfg); err != nil {
+		return err
+	}
+	req, resp := sc.Client.PutBucketLifecycleConfigurationRequest(&s3.PutBucketLifecycleConfigurationInput{
+		Bucket:                 aws.String(bucket),
+		LifecycleConfiguration: cfg,
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put lifecycle failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(cfg); err != nil {
+			return err
+		}
+	} else if sc.lineOutput() || sc.recordOutput() {
+		return sc.printLifecycleRules(cfg.Rules)
+	}
+	return nil
+}
+
+// BucketLifecycleDelete delete a Bucket's lifecycle rules
+func (sc *S3Cli) BucketLifecycleDelete(ctx context.Context, bucket string) error {
+	req, resp := sc.Client.DeleteBucketLifecycleRequest(&s3.DeleteBucketLifecycleInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("delete lifecycle failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package s3cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_loadLifecycleConfig(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"lifecycle.yaml": `
+rules:
+  - id: logs
+    status: Enabled
+    filter:
+      prefix: logs/
+    expiration:
+      days: 30
+  - id: mpu
+    status: Enabled
+    abortIncompleteMultipartUpload:
+      daysAfterInitiation: 7
+`,
+		"lifecycle.json": `{"Rules": [
+  {"ID": "logs", "Status": "Enabled", "Filter": {"Prefix": "logs/"}, "Expiration": {"Days": 30}},
+  {"ID": "mpu", "Status": "Enabled", "AbortIncompleteMultipartUpload": {"DaysAfterInitiation": 7}}
+]}`,
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadLifecycleConfig(filename)
+		if err != nil {
+			t.Fatalf("LoadLifecycleConfig %s failed: %s", name, err)
+		}
+		if err := validateLifecycle(cfg); err != nil {
+			t.Errorf("%s expect valid, got %s", name, err)
+		}
+		if len(cfg.Rules) != 2 || lifecycleActions(cfg.Rules[0]) != "expire=30d" || lifecycleFilter(cfg.Rules[0]) != "prefix=logs/" {
+			t.Fatalf("%s rules mismatch: %v", name, cfg.Rules)
+		}
+		if lifecycleFilter(cfg.Rules[1]) != "prefix=" {
+			t.Errorf("%s rule without filter expect empty prefix filter, got %s", name, lifecycleFilter(cfg.Rules[1]))
+		}
+	}
+
+	filename := filepath.Join(dir, "typo.yaml")
+	if err := os.WriteFile(filename, []byte("rules:\n  - id: x\n    expire: 1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadLifecycleConfig(filename); err == nil {
+		t.Errorf("unknown field expect error")
+	}
+}
+
+func Test_lifecycleRuleOptions(t *testing.T) {
+	opt := LifecycleRuleOptions{
+		ID:                       "s3cli",
+		Prefix:                   "tmp/",
+		ExpireDays:               30,
+		AbortMPUDays:             7,
+		NoncurrentTransitionDays: 10,
+		NoncurrentStorageClass:   s3.TransitionStorageClassGlacier,
+	}
+	r := opt.Rule()
+	if err := validateLifecycle(&s3.BucketLifecycleConfiguration{Rules: []*s3.LifecycleRule{r}}); err != nil {
+		t.Fatal("rule of options expect valid, got ", err)
+	}
+	if got := lifecycleActions(r); got != "expire=30d,noncurrent-transition=10d:GLACIER,abort-mpu=7d" {
+		t.Errorf("unexpected actions %s", got)
+	}
+	if got := lifecycleFilter(r); got != "prefix=tmp/" {
+		t.Errorf("unexpected filter %s", got)
+	}
+}
+
+func Test_validateLifecycle(t *testing.T) {
+	rule := func(f func(r *s3.LifecycleRule)) *s3.LifecycleRule {
+		r := &s3.LifecycleRule{
+			ID:         aws.String("r"),
+			Status:     aws.String(s3.ExpirationStatusEnabled),
+			Filter:     &s3.LifecycleRuleFilter{Prefix: aws.String("")},
+			Expiration: &s3.LifecycleExpiration{Days: aws.Int64(1)},
+		}
+		f(r)
+		return r
+	}
+	cases := map[string][]*s3.LifecycleRule{
+		"no rule":        nil,
+		"invalid status": {rule(func(r *s3.LifecycleRule) { r.Status = aws.String("enabled") })},
+		"duplicate id":   {rule(func(r *s3.LifecycleRule) {}), rule(func(r *s3.LifecycleRule) {})},
+		"prefix and filter": {rule(func(r *s3.LifecycleRule) {
+			r.Prefix = aws.String("a/")
+		})},
+		"no action":                         {rule(func(r *s3.LifecycleRule) { r.Expiration = nil })},
+		"zero days":                         {rule(func(r *s3.LifecycleRule) { r.Expiration.Days = aws.Int64(0) })},
+		"expiration days and delete marker": {rule(func(r *s3.LifecycleRule) { r.Expiration.ExpiredObjectDeleteMarker = aws.Bool(true) })},
+		"abort mpu days": {rule(func(r *s3.LifecycleRule) {
+			r.AbortIncompleteMultipartUpload = &s3.AbortIncompleteMultipartUpload{DaysAfterInitiation: aws.Int64(-1)}
+		})},
+		"storage class": {rule(func(r *s3.LifecycleRule) {
+			r.NoncurrentVersionTransitions = []*s3.NoncurrentVersionTransition{{NoncurrentDays: aws.Int64(1), StorageClass: aws.String("COLD")}}
+		})},
+		"transition days and date": {rule(func(r *s3.LifecycleRule) {
+			r.Transitions = []*s3.Transition{{StorageClass: aws.String(s3.TransitionStorageClassGlacier)}}
+		})},
+		"nil rule":       {rule(func(r *s3.LifecycleRule) {}), nil},
+		"nil transition": {rule(func(r *s3.LifecycleRule) { r.Transitions = []*s3.Transition{nil} })},
+		"nil noncurrent transition": {rule(func(r *s3.LifecycleRule) {
+			r.NoncurrentVersionTransitions = []*s3.NoncurrentVersionTransition{nil}
+		})},
+	}
+	for name, rules := range cases {
+		if err := validateLifecycle(&s3.BucketLifecycleConfiguration{Rules: rules}); err == nil {
+			t.Errorf("%s expect error", name)
+		}
+	}
+}
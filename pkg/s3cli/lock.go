@@ -0,0 +1,252 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var (
+	retentionSchema = schema{
+		columns:      []string{"Mode", "RetainUntilDate"},
+		simple:       []string{"Mode", "RetainUntilDate"},
+		simpleFormat: "%v %v",
+	}
+	legalHoldSchema = schema{
+		columns: []string{"Status"},
+	}
+)
+
+// lockMode validate a Object Lock retention mode(case-insensitive)
+func lockMode(mode string) (string, error) {
+	switch m := strings.ToUpper(mode); m {
+	case s3.ObjectLockModeGovernance, s3.ObjectLockModeCompliance:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid lock mode %s(GOVERNANCE or COMPLIANCE)", mode)
+}
+
+// NewDefaultRetention create a Bucket default retention of mode and period(days or years),
+// returns nil if neither mode nor period is set
+func NewDefaultRetention(mode string, days, years int64) (*s3.DefaultRetention, error) {
+	if mode == "" && days == 0 && years == 0 {
+		return nil, nil
+	}
+	m, err := lockMode(mode)
+	if err != nil {
+		return nil, err
+	}
+	if days < 0 || years < 0 || (days > 0) == (years > 0) {
+		return nil, errors.New("default retention requires a positive period of days or years(not both)")
+	}
+	r := &s3.DefaultRetention{Mode: aws.String(m)}
+	if days > 0 {
+		r.Days = aws.Int64(days)
+	} else {
+		r.Years = aws.Int64(years)
+	}
+	return r, nil
+}
+
+// ParseRetainUntil parse a retain until date of format 2006-01-02, RFC3339 or days from now(30d)
+func ParseRetainUntil(v string) (time.Time, error) {
+	if strings.HasSuffix(v, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(v, "d"))
+		if err == nil && days > 0 {
+			return time.Now().UTC().AddDate(0, 0, days), nil
+		}
+	}
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %s(format 2006-01-02, RFC3339 or days from now like 30d)", v)
+}
+
+// ObjectLockOptions is the Object Lock settings of a new Object
+type ObjectLockOptions struct {
+	LockMode        string    // Object Lock retention mode(GOVERNANCE or COMPLIANCE)
+	LockRetainUntil time.Time // Object Lock retain until date, required with LockMode
+	LegalHold       bool      // place a legal hold on the Object
+}
+
+// validate validate the Object Lock settings of o
+func (o ObjectLockOptions) validate() error {
+	if o.LockMode == "" && o.LockRetainUntil.IsZero() {
+		return nil
+	}
+	if o.LockMode == "" || o.LockRetainUntil.IsZero() {
+		return errors.New("lock mode and retain until date are required together")
+	}
+	if _, err := lockMode(o.LockMode); err != nil {
+		return err
+	}
+	if !o.LockRetainUntil.After(time.Now()) {
+		return fmt.Errorf("retain until date %s is not in the future", o.LockRetainUntil.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// lockMode return the Object Lock mode of o, nil if not set
+func (o ObjectLockOptions) lockMode() *string {
+	if o.LockMode == "" {
+		return nil
+	}
+	return aws.String(strings.ToUpper(o.LockMode))
+}
+
+// lockRetainUntil return the Object Lock retain until date of o, nil if not set
+func (o ObjectLockOptions) lockRetainUntil() *time.Time {
+	if o.LockRetainUntil.IsZero() {
+		return nil
+	}
+	return aws.Time(o.LockRetainUntil)
+}
+
+// legalHold return the legal hold status of o, nil if no legal hold
+func (o ObjectLockOptions) legalHold() *string {
+	if !o.LegalHold {
+		return nil
+	}
+	return aws.String(s3.ObjectLockLegalHoldStatusOn)
+}
+
+// ObjectRetentionGet get a Object's(or its version's) retention
+func (sc *S3Cli) ObjectRetentionGet(ctx context.Context, bucket, key, version string) (*s3.GetObjectRetentionOutput, error) {
+	in := &s3.GetObjectRetentionInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	req, resp := sc.Client.GetObjectRetentionRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get object retention failed: %w", err)
+	}
+	return resp, nil
+}
+
+// ObjectRetentionPut put a Object's(or its version's) retention of mode until date,
+// an empty mode and zero date clear the retention(GOVERNANCE mode requires bypassGovernance)
+func (sc *S3Cli) ObjectRetentionPut(ctx context.Context, bucket, key, version, mode string, until time.Time, bypassGovernance bool) error {
+	retention := &s3.ObjectLockRetention{}
+	if mode != "" || !until.IsZero() {
+		opt := ObjectLockOptions{LockMode: mode, LockRetainUntil: until}
+		if err := opt.validate(); err != nil {
+			return err
+		}
+		retention.Mode = opt.lockMode()
+		retention.RetainUntilDate = opt.lockRetainUntil()
+	}
+	in := &s3.PutObjectRetentionInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		Retention: retention,
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	if bypassGovernance {
+		in.BypassGovernanceRetention = aws.Bool(true)
+	}
+	req, resp := sc.Client.PutObjectRetentionRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put object retention failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// ObjectLegalHoldGet get a Object's(or its version's) legal hold status
+func (sc *S3Cli) ObjectLegalHoldGet(ctx context.Context, bucket, key, version string) (*s3.GetObjectLegalHoldOutput, error) {
+	in := &s3.GetObjectLegalHoldInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	req, resp := sc.Client.GetObjectLegalHoldRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get object legal hold failed: %w", err)
+	}
+	return resp, nil
+}
+
+// ObjectLegalHoldPut turn a Object's(or its version's) legal hold on or off
+func (sc *S3Cli) ObjectLegalHoldPut(ctx context.Context, bucket, key, version string, on bool) error {
+	status := s3.ObjectLockLegalHoldStatusOff
+	if on {
+		status = s3.ObjectLockLegalHoldStatusOn
+	}
+	in := &s3.PutObjectLegalHoldInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		LegalHold: &s3.ObjectLockLegalHold{Status: aws.String(status)},
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	req, resp := sc.Client.PutObjectLegalHoldRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put object legal hold failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
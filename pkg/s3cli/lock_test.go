@@ -0,0 +1,113 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_newDefaultRetention(t *testing.T) {
+	r, err := NewDefaultRetention("", 0, 0)
+	if err != nil || r != nil {
+		t.Errorf("no mode and period expect nil retention, got %v, %v", r, err)
+	}
+	r, err = NewDefaultRetention("governance", 30, 0)
+	if err != nil {
+		t.Fatal("NewDefaultRetention failed: ", err)
+	}
+	if aws.StringValue(r.Mode) != s3.ObjectLockRetentionModeGovernance || aws.Int64Value(r.Days) != 30 || r.Years != nil {
+		t.Errorf("unexpected retention %v", r)
+	}
+	cases := []struct {
+		mode        string
+		days, years int64
+	}{
+		{"LEGAL", 1, 0},
+		{"COMPLIANCE", 0, 0},
+		{"COMPLIANCE", 1, 1},
+		{"COMPLIANCE", -1, 0},
+		{"", 1, 0},
+	}
+	for _, c := range cases {
+		if _, err := NewDefaultRetention(c.mode, c.days, c.years); err == nil {
+			t.Errorf("%+v expect error", c)
+		}
+	}
+}
+
+func Test_parseRetainUntil(t *testing.T) {
+	until, err := ParseRetainUntil("2030-01-02")
+	if err != nil || !until.Equal(time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected date %v, %v", until, err)
+	}
+	until, err = ParseRetainUntil("2030-01-02T03:04:05+08:00")
+	if err != nil || until.UTC().Hour() != 19 {
+		t.Errorf("unexpected RFC3339 date %v, %v", until, err)
+	}
+	until, err = ParseRetainUntil("30d")
+	if err != nil || until.Sub(time.Now()) < 29*24*time.Hour {
+		t.Errorf("unexpected relative date %v, %v", until, err)
+	}
+	for _, v := range []string{"", "0d", "-1d", "d", "2030/01/02"} {
+		if _, err := ParseRetainUntil(v); err == nil {
+			t.Errorf("ParseRetainUntil(%q) expect error", v)
+		}
+	}
+}
+
+func Test_validateLock(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	valid := []ObjectLockOptions{
+		{},
+		{LegalHold: true},
+		{LockMode: "compliance", LockRetainUntil: future},
+	}
+	for _, o := range valid {
+		if err := o.validate(); err != nil {
+			t.Errorf("%+v expect valid, got %s", o, err)
+		}
+	}
+	invalid := []ObjectLockOptions{
+		{LockMode: "GOVERNANCE"},
+		{LockRetainUntil: future},
+		{LockMode: "LEGAL", LockRetainUntil: future},
+		{LockMode: "GOVERNANCE", LockRetainUntil: time.Now().Add(-time.Hour)},
+	}
+	for _, o := range invalid {
+		if err := o.validate(); err == nil {
+			t.Errorf("%+v expect error", o)
+		}
+	}
+}
+
+func Test_objectLockHeader(t *testing.T) {
+	sc, h := newHeaderRecorderCli(t, "bucket-lock")
+	ctx := context.Background()
+
+	until := time.Date(2100, 1, 2, 0, 0, 0, 0, time.UTC)
+	opt := ObjectOptions{ObjectLockOptions: ObjectLockOptions{LockMode: "governance", LockRetainUntil: until, LegalHold: true}}
+	if _, err := sc.PutObject(ctx, "bucket-lock", "key", "", nil, false, bytes.NewReader(testObjectContent), opt); err != nil {
+		t.Fatal("putObject failed: ", err)
+	}
+	header := h.headers[http.MethodPut]
+	if header.Get("X-Amz-Object-Lock-Mode") != s3.ObjectLockModeGovernance ||
+		header.Get("X-Amz-Object-Lock-Retain-Until-Date") != "2100-01-02T00:00:00Z" ||
+		header.Get("X-Amz-Object-Lock-Legal-Hold") != s3.ObjectLockLegalHoldStatusOn {
+		t.Errorf("putObject unexpected lock headers %v", header)
+	}
+	if header.Get("Content-Md5") == "" {
+		t.Errorf("putObject with lock expect Content-MD5")
+	}
+
+	if _, err := sc.DeleteObject(ctx, "bucket-lock", "key", true); err != nil {
+		t.Fatal("deleteObject failed: ", err)
+	}
+	if got := h.headers[http.MethodDelete].Get("X-Amz-Bypass-Governance-Retention"); got != "true" {
+		t.Errorf("deleteObject expect bypass governance header, got %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var loggingSchema = schema{
+	columns:      []string{"TargetBucket", "TargetPrefix"},
+	simple:       []string{"TargetBucket", "TargetPrefix"},
+	simpleFormat: "%v %v",
+}
+
+// loggingStatus create the logging status of target bucket and prefix,
+// an empty target bucket disable the access logging
+func loggingStatus(targetBucket, targetPrefix string) (*s3.BucketLoggingStatus, error) {
+	status := &s3.BucketLoggingStatus{}
+	if targetBucket == "" {
+		if targetPrefix != "" {
+			return nil, errors.New("target prefix requires a target bucket")
+		}
+		return status, nil
+	}
+	status.LoggingEnabled = &s3.LoggingEnabled{
+		TargetBucket: aws.String(targetBucket),
+		TargetPrefix: aws.String(targetPrefix),
+	}
+	return status, status.Validate()
+}
+
+// BucketLoggingGet get a Bucket's server access logging
+func (sc *S3Cli) BucketLoggingGet(ctx context.Context, bucket string) (*s3.GetBucketLoggingOutput, error) {
+	req, resp := sc.Client.GetBucketLoggingRequest(&s3.GetBucketLoggingInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get logging failed: %w", err)
+	}
+	return resp, nil
+}
+
+// BucketLoggingPut put a Bucket's server access logging to targetBucket with targetPrefix,
+// an empty targetBucket disable the access logging
+func (sc *S3Cli) BucketLoggingPut(ctx context.Context, bucket, targetBucket, targetPrefix string) error {
+	status, err := loggingStatus(targetBucket, targetPrefix)
+	if err != nil {
+		return err
+	}
+	req, resp := sc.Client.PutBucketLoggingRequest(&s3.PutBucketLoggingInput{
+		Bucket:              aws.String(bucket),
+		BucketLoggingStatus: status,
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err = req.Send()
+	if err != nil {
+		return fmt.Errorf("put logging failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(status); err != nil {
+			return err
+		}
+	}
+	return nil
+}
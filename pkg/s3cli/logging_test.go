@@ -0,0 +1,24 @@
+package s3cli
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func Test_loggingStatus(t *testing.T) {
+	status, err := loggingStatus("log-bucket", "access/")
+	if err != nil {
+		t.Fatal("loggingStatus failed: ", err)
+	}
+	if l := status.LoggingEnabled; aws.StringValue(l.TargetBucket) != "log-bucket" || aws.StringValue(l.TargetPrefix) != "access/" {
+		t.Errorf("unexpected logging %v", l)
+	}
+	status, err = loggingStatus("", "")
+	if err != nil || status.LoggingEnabled != nil {
+		t.Errorf("empty target expect disabled logging, got %v, %v", status, err)
+	}
+	if _, err := loggingStatus("", "access/"); err == nil {
+		t.Errorf("prefix without target bucket expect error")
+	}
+}
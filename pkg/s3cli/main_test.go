@@ -0,0 +1,55 @@
+package s3cli
+
+import (
+	"bytes"
+	"log"
+	mand "math/rand"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+var (
+	s3Backend *s3mem.Backend
+)
+
+var s3cliTest = S3Cli{
+	AccessKey: "my-ak",
+	SecretKey: "my-sk",
+	Region:    s3.BucketLocationConstraintCnNorth1,
+	PathStyle: true,
+	Client:    nil,
+}
+
+func TestMain(m *testing.M) {
+	mand.Seed(time.Now().UTC().UnixNano())
+	// init fake s3
+	s3Backend = s3mem.New()
+	faker := gofakes3.New(s3Backend)
+	ts := httptest.NewServer(faker.Server())
+	defer ts.Close()
+	s3cliTest.Endpoint = ts.URL
+	client, err := NewS3Client(&s3cliTest)
+	if err != nil {
+		log.Fatal("newS3Client", err)
+		os.Exit(1)
+	}
+	s3cliTest.Client = client
+	if err := s3Backend.CreateBucket(testBucketName); err != nil {
+		log.Fatal("backend CreateBucket error: ", err)
+		os.Exit(1)
+	}
+
+	_, err = s3Backend.PutObject(testBucketName, testObjectKey, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		log.Fatal("backend PutObject error: ", err)
+		os.Exit(1)
+	}
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,184 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var notificationSchema = schema{
+	columns: []string{"ID", "Type", "Arn", "Events", "Filter"},
+	simple:  []string{"ID", "Type", "Arn"},
+}
+
+// notificationTarget is a queue, topic or lambda notification of a Bucket
+type notificationTarget struct {
+	kind   string // Queue, Topic or Lambda
+	id     *string
+	arn    *string
+	events []*string
+	filter *s3.NotificationConfigurationFilter
+}
+
+// notificationTargets return the queue, topic and lambda notifications of cfg
+func notificationTargets(cfg *s3.NotificationConfiguration) []notificationTarget {
+	targets := []notificationTarget{}
+	for _, c := range cfg.QueueConfigurations {
+		if c != nil {
+			targets = append(targets, notificationTarget{"Queue", c.Id, c.QueueArn, c.Events, c.Filter})
+		}
+	}
+	for _, c := range cfg.TopicConfigurations {
+		if c != nil {
+			targets = append(targets, notificationTarget{"Topic", c.Id, c.TopicArn, c.Events, c.Filter})
+		}
+	}
+	for _, c := range cfg.LambdaFunctionConfigurations {
+		if c != nil {
+			targets = append(targets, notificationTarget{"Lambda", c.Id, c.LambdaFunctionArn, c.Events, c.Filter})
+		}
+	}
+	return targets
+}
+
+// LoadNotificationConfig load the notification configuration(JSON or YAML) of file
+func LoadNotificationConfig(filename string) (*s3.NotificationConfiguration, error) {
+	cfg := &s3.NotificationConfiguration{}
+	if err := decodeFile(filename, cfg); err != nil {
+		return nil, fmt.Errorf("load notification %s failed: %w", filename, err)
+	}
+	return cfg, nil
+}
+
+// validateNotification check a notification configuration before put it,
+// an empty configuration disable the notifications of a Bucket
+func validateNotification(cfg *s3.NotificationConfiguration) error {
+	if cfg == nil {
+		return errors.New("no notification configuration")
+	}
+	ids := map[string]bool{}
+	for i, t := range notificationTargets(cfg) {
+		name := aws.StringValue(t.id)
+		if name == "" {
+			name = fmt.Sprintf("%s #%d", t.kind, i+1)
+		} else if ids[name] {
+			return fmt.Errorf("duplicate notification ID %s", name)
+		}
+		ids[name] = true
+
+		if aws.StringValue(t.arn) == "" {
+			return fmt.Errorf("notification %s: no %s ARN", name, t.kind)
+		}
+		if len(t.events) == 0 {
+			return fmt.Errorf("notification %s: no event", name)
+		}
+		for _, e := range t.events {
+			if e == nil {
+				return fmt.Errorf("notification %s: empty event", name)
+			}
+			if !strings.HasPrefix(aws.StringValue(e), "s3:") {
+				return fmt.Errorf("notification %s: invalid event %q(s3:ObjectCreated:* etc.)", name, aws.StringValue(e))
+			}
+		}
+		if t.filter == nil || t.filter.Key == nil {
+			continue
+		}
+		names := map[string]bool{}
+		for _, r := range t.filter.Key.FilterRules {
+			if r == nil {
+				return fmt.Errorf("notification %s: empty filter rule", name)
+			}
+			n := strings.ToLower(aws.StringValue(r.Name))
+			if n != s3.FilterRuleNamePrefix && n != s3.FilterRuleNameSuffix {
+				return fmt.Errorf("notification %s: invalid filter rule name %q(prefix or suffix)", name, aws.StringValue(r.Name))
+			}
+			if names[n] {
+				return fmt.Errorf("notification %s: duplicate %s filter rule", name, n)
+			}
+			names[n] = true
+		}
+	}
+	return cfg.Validate()
+}
+
+// notificationFilter format the key filter of a notification
+func notificationFilter(f *s3.NotificationConfigurationFilter) string {
+	if f == nil || f.Key == nil {
+		return ""
+	}
+	items := []string{}
+	for _, r := range f.Key.FilterRules {
+		items = append(items, strings.ToLower(aws.StringValue(r.Name))+"="+aws.StringValue(r.Value))
+	}
+	return strings.Join(items, ",")
+}
+
+// printNotifications print the notifications of cfg as records
+func (sc *S3Cli) printNotifications(cfg *s3.NotificationConfiguration) error {
+	p := sc.newPrinter(notificationSchema)
+	for _, t := range notificationTargets(cfg) {
+		if err := p.add(aws.StringValue(t.id), t.kind, aws.StringValue(t.arn), strings.Join(aws.StringValueSlice(t.events), ","), notificationFilter(t.filter)); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// BucketNotificationGet get a Bucket's notification configuration
+func (sc *S3Cli) BucketNotificationGet(ctx context.Context, bucket string) (*s3.NotificationConfiguration, error) {
+	req, resp := sc.Client.GetBucketNotificationConfigurationRequest(&s3.GetBucketNotificationConfigurationRequest{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get notification failed: %w", err)
+	}
+	return resp, nil
+}
+
+// BucketNotificationPut validate and put(replace) a Bucket's notification configuration
+func (sc *S3Cli) BucketNotificationPut(ctx context.Context, bucket string, cfg *s3.NotificationConfiguration) error {
+	if err := validateNotification(cfg); err != nil {
+		return err
+	}
+	req, resp := sc.Client.PutBucketNotificationConfigurationRequest(&s3.PutBucketNotificationConfigurationInput{
+		Bucket:                    aws.String(bucket),
+		NotificationConfiguration: cfg,
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put notification failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package s3cli
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_loadNotificationConfig(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "notification.json")
+	content := `{
+  "QueueConfigurations": [{
+    "Id": "created", "QueueArn": "arn:aws:sqs:us-east-1:123456789012:queue",
+    "Events": ["s3:ObjectCreated:*"],
+    "Filter": {"Key": {"FilterRules": [{"Name": "prefix", "Value": "logs/"}, {"Name": "Suffix", "Value": ".gz"}]}}
+  }],
+  "TopicConfigurations": [{"TopicArn": "arn:aws:sns:us-east-1:123456789012:topic", "Events": ["s3:ObjectRemoved:*"]}],
+  "LambdaFunctionConfigurations": [{"LambdaFunctionArn": "arn:aws:lambda:us-east-1:123456789012:function:f", "Events": ["s3:ObjectRestore:*"]}]
+}`
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadNotificationConfig(filename)
+	if err != nil {
+		t.Fatal("LoadNotificationConfig failed: ", err)
+	}
+	if err := validateNotification(cfg); err != nil {
+		t.Errorf("expect valid, got %s", err)
+	}
+	targets := notificationTargets(cfg)
+	if len(targets) != 3 || targets[1].kind != "Topic" || targets[2].kind != "Lambda" {
+		t.Fatalf("unexpected notifications %v", targets)
+	}
+	if got := notificationFilter(targets[0].filter); got != "prefix=logs/,suffix=.gz" {
+		t.Errorf("unexpected filter %s", got)
+	}
+
+	if err := os.WriteFile(filename, []byte(`{"QueueConfigurations": [{"Queue": "arn"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadNotificationConfig(filename); err == nil {
+		t.Errorf("unknown field expect error")
+	}
+}
+
+func Test_validateNotification(t *testing.T) {
+	if err := validateNotification(&s3.NotificationConfiguration{}); err != nil {
+		t.Errorf("empty configuration expect valid, got %s", err)
+	}
+	queue := func(f func(c *s3.QueueConfiguration)) *s3.QueueConfiguration {
+		c := &s3.QueueConfiguration{
+			Id:       aws.String("q"),
+			QueueArn: aws.String("arn:aws:sqs:us-east-1:123456789012:queue"),
+			Events:   aws.StringSlice([]string{s3.EventS3ObjectCreated}),
+		}
+		f(c)
+		return c
+	}
+	rules := func(names ...string) *s3.NotificationConfigurationFilter {
+		f := &s3.NotificationConfigurationFilter{Key: &s3.KeyFilter{}}
+		for _, n := range names {
+			f.Key.FilterRules = append(f.Key.FilterRules, &s3.FilterRule{Name: aws.String(n), Value: aws.String("v")})
+		}
+		return f
+	}
+	cases := map[string][]*s3.QueueConfiguration{
+		"duplicate id":     {queue(func(c *s3.QueueConfiguration) {}), queue(func(c *s3.QueueConfiguration) {})},
+		"no arn":           {queue(func(c *s3.QueueConfiguration) { c.QueueArn = nil })},
+		"no event":         {queue(func(c *s3.QueueConfiguration) { c.Events = nil })},
+		"invalid event":    {queue(func(c *s3.QueueConfiguration) { c.Events = aws.StringSlice([]string{"ObjectCreated:*"}) })},
+		"filter rule name": {queue(func(c *s3.QueueConfiguration) { c.Filter = rules("contains") })},
+		"duplicate rule":   {queue(func(c *s3.QueueConfiguration) { c.Filter = rules("prefix", "Prefix") })},
+		"nil event":        {queue(func(c *s3.QueueConfiguration) { c.Events = []*string{nil} })},
+		"nil filter rule": {queue(func(c *s3.QueueConfiguration) {
+			c.Filter = &s3.NotificationConfigurationFilter{Key: &s3.KeyFilter{FilterRules: []*s3.FilterRule{nil}}}
+		})},
+	}
+	for name, queues := range cases {
+		if err := validateNotification(&s3.NotificationConfiguration{QueueConfigurations: queues}); err == nil {
+			t.Errorf("%s expect error", name)
+		}
+	}
+}
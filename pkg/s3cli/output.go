@@ -0,0 +1,518 @@
+package s3cli
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/jmespath/go-jmespath"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputTable  = "table"
+	OutputCSV    = "csv"
+	OutputYAML   = "yaml"
+	OutputNDJSON = "ndjson"
+)
+
+// outputFormats is all supported output formats
+var outputFormats = []string{OutputSimple, OutputLine, OutputJSON, OutputVerbose, OutputTable, OutputCSV, OutputYAML, OutputNDJSON, OutputTemplate}
+
+// validOutput check the output format(or its short name)
+func validOutput(format string) error {
+	switch format {
+	case outputV, outputS, outputL, outputJ:
+		return nil
+	}
+	for _, v := range outputFormats {
+		if format == v {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid output format %s(%s)", format, strings.Join(outputFormats, ","))
+}
+
+// recordOutput report whether the output format is table, csv, yaml, ndjson or template
+func (sc *S3Cli) recordOutput() bool {
+	switch sc.Output {
+	case OutputTable, OutputCSV, OutputYAML, OutputNDJSON, OutputTemplate:
+		return true
+	}
+	return false
+}
+
+// SetOutput validate the output format, set the JSON filter and output template,
+// filter switch the default output to json and template to template
+func (sc *S3Cli) SetOutput(filter, tmpl string, outputChanged bool) error {
+	if err := validOutput(sc.Output); err != nil {
+		return err
+	}
+	if tmpl != "" {
+		if sc.Output != OutputTemplate {
+			if outputChanged {
+				return fmt.Errorf("template requires template output")
+			}
+			sc.Output = OutputTemplate
+		}
+		t, err := parseTemplate(tmpl)
+		if err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+		sc.tmpl = t
+	} else if sc.Output == OutputTemplate {
+		return fmt.Errorf("template output requires a template")
+	}
+	if err := sc.setFilter(filter); err != nil {
+		return err
+	}
+	if sc.filter != nil && !sc.jsonOutput() {
+		if outputChanged {
+			return fmt.Errorf("filter requires json output")
+		}
+		sc.Output = OutputJSON
+	}
+	return nil
+}
+
+// setFilter compile the JMESPath expression applied to JSON output
+func (sc *S3Cli) setFilter(expr string) error {
+	if expr == "" {
+		sc.filter = nil
+		return nil
+	}
+	jp, err := jmespath.Compile(expr)
+	if err != nil {
+		return fmt.Errorf("invalid filter %s: %w", expr, err)
+	}
+	sc.filter = jp
+	return nil
+}
+
+// applyFilter apply JMESPath expression to the JSON form of v
+func applyFilter(jp *jmespath.JMESPath, v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return jp.Search(doc)
+}
+
+// printJSON print v(filtered if filter is set) as indented JSON
+func (sc *S3Cli) printJSON(v interface{}) error {
+	if sc.filter != nil {
+		filtered, err := applyFilter(sc.filter, v)
+		if err != nil {
+			return fmt.Errorf("filter failed: %w", err)
+		}
+		v = filtered
+	}
+	jo, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal json failed: %w", err)
+	}
+	fmt.Fprintf(sc.out(), "%s", jo)
+	return nil
+}
+
+// schema is the stable record layout of a command output
+type schema struct {
+	columns []string // record fields(in order) of line, table, csv, yaml and ndjson output
+	simple  []string // columns of simple output, default the last column
+	// fmt format of simple columns, default join simple columns with tab
+	simpleFormat string
+}
+
+var (
+	bucketSchema = schema{
+		columns: []string{"CreationDate", "Owner", "Name"},
+		simple:  []string{"Name"},
+	}
+	objectSchema = schema{
+		columns: []string{"StorageClass", "LastModified", "ETag", "Size", "Owner", "Key"},
+		simple:  []string{"Key"},
+	}
+	indexedObjectSchema = schema{
+		columns: []string{"Index", "StorageClass", "LastModified", "ETag", "Size", "Owner", "Key"},
+		simple:  []string{"Index", "Key"},
+	}
+	versionSchema = schema{
+		columns: []string{"LastModified", "VersionId", "IsLatest", "DeleteMarker", "ETag", "Size", "Key"},
+		simple:  []string{"VersionId", "Key"},
+	}
+	headSchema = schema{
+		columns: []string{"Key", "Size", "LastModified", "ETag", "ContentType", "StorageClass", "VersionId"},
+		simple:  []string{"Size", "LastModified"},
+	}
+	uploadSchema = schema{
+		columns: []string{"Initiated", "UploadId", "Key"},
+		simple:  []string{"UploadId", "Key"},
+	}
+	partSchema = schema{
+		columns:      []string{"PartNumber", "LastModified", "ETag", "Size"},
+		simple:       []string{"PartNumber", "ETag"},
+		simpleFormat: "%v:%v",
+	}
+	putSchema = schema{
+		columns: []string{"Time", "Action", "ETag", "Key"},
+		simple:  []string{"Key"},
+	}
+	uploadResultSchema = schema{
+		columns: []string{"File", "Key", "ETag", "Error"},
+		simple:  []string{"Key", "Error"},
+	}
+	downloadResultSchema = schema{
+		columns: []string{"Key", "File", "Size", "Skipped", "Error"},
+		simple:  []string{"File", "Error"},
+	}
+	renameSchema = schema{
+		columns: []string{"Source", "Target", "Error"},
+		simple:  []string{"Target", "Error"},
+	}
+	deleteSchema = schema{
+		columns: []string{"Key", "VersionId", "DeleteMarker"},
+		simple:  []string{"Key"},
+	}
+	mpuCreateSchema = schema{
+		columns: []string{"Bucket", "Key", "UploadId"},
+		simple:  []string{"UploadId"},
+	}
+	mpuResultSchema = schema{
+		columns:      []string{"Location", "UploadId", "ETag", "VersionId", "Uploaded", "Reused"},
+		simple:       []string{"Location", "UploadId", "ETag", "VersionId"},
+		simpleFormat: "%v %v %v %v",
+	}
+	partUploadSchema = schema{
+		columns:      []string{"PartNumber", "Offset", "Size", "ETag"},
+		simple:       []string{"PartNumber", "ETag"},
+		simpleFormat: "%v:%v",
+	}
+	bucketCreateSchema = schema{
+		columns: []string{"Location"},
+	}
+	bucketHeadSchema = schema{
+		columns: []string{"Bucket", "Status"},
+		simple:  []string{"Status"},
+	}
+	encryptionSchema = schema{
+		columns: []string{"SSEAlgorithm", "KMSMasterKeyID", "BucketKeyEnabled"},
+		simple:  []string{"SSEAlgorithm"},
+	}
+	corsSchema = schema{
+		columns:      []string{"ID", "AllowedMethods", "AllowedOrigins", "AllowedHeaders", "ExposeHeaders", "MaxAgeSeconds"},
+		simple:       []string{"AllowedMethods", "AllowedOrigins"},
+		simpleFormat: "%v %v",
+	}
+	aclSchema = schema{
+		columns: []string{"Grantee", "Type", "Permission"},
+		simple:  []string{"Grantee", "Permission"},
+	}
+	policySchema = schema{
+		columns: []string{"Policy"},
+	}
+	versioningSchema = schema{
+		columns: []string{"Status", "MFADelete"},
+		simple:  []string{"Status"},
+	}
+	objectLockSchema = schema{
+		columns: []string{"ObjectLockEnabled", "Mode", "Days", "Years"},
+		simple:  []string{"ObjectLockEnabled"},
+	}
+	aliasSchema = schema{
+		columns:      []string{"Name", "Endpoint", "Region", "SignatureVersion", "PathStyle", "AccessKey"},
+		simple:       []string{"Name", "Endpoint"},
+		simpleFormat: "%v %v",
+	}
+)
+
+// printer print the records of a command in simple, line, table, csv, yaml, ndjson or template format,
+// line, csv, ndjson and template records are printed immediately, table and yaml at flush
+type printer struct {
+	format string
+	schema schema
+	w      io.Writer
+	simple []int              // index of simple columns
+	tmpl   *template.Template // template of template output, columns are its fields
+
+	header bool // csv header printed
+	csv    *csv.Writer
+	table  *tabwriter.Writer
+	yaml   *yaml.Node
+}
+
+// newPrinter create a printer of sc output format
+func (sc *S3Cli) newPrinter(s schema) *printer {
+	p := newPrinter(sc.Output, s, sc.out())
+	p.tmpl = sc.tmpl
+	return p
+}
+
+// newPrinter create a printer print records to w
+func newPrinter(format string, s schema, w io.Writer) *printer {
+	switch format {
+	case outputS, "":
+		format = OutputSimple
+	case outputL:
+		format = OutputLine
+	}
+	p := &printer{format: format, schema: s, w: w}
+	for _, name := range s.simple {
+		for i, c := range s.columns {
+			if c == name {
+				p.simple = append(p.simple, i)
+			}
+		}
+	}
+	if len(p.simple) == 0 {
+		p.simple = []int{len(s.columns) - 1}
+	}
+	switch format {
+	case OutputCSV:
+		p.csv = csv.NewWriter(w)
+	case OutputTable:
+		p.table = tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(p.table, strings.ToUpper(strings.Join(s.columns, "\t")))
+	case OutputYAML:
+		p.yaml = &yaml.Node{Kind: yaml.SequenceNode}
+	}
+	return p
+}
+
+// formatValue format a record value as text
+func formatValue(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	case *time.Time:
+		if t == nil {
+			return ""
+		}
+		return formatValue(*t)
+	}
+	return fmt.Sprint(v)
+}
+
+// add print(or buffer) a record, values are in order of schema columns
+func (p *printer) add(values ...interface{}) error {
+	if len(values) != len(p.schema.columns) {
+		return fmt.Errorf("record has %d values, schema has %d columns", len(values), len(p.schema.columns))
+	}
+	text := make([]string, len(values))
+	for i, v := range values {
+		text[i] = formatValue(v)
+	}
+	switch p.format {
+	case OutputLine:
+		_, err := fmt.Fprintln(p.w, strings.Join(text, " "))
+		return err
+	case OutputTable:
+		_, err := fmt.Fprintln(p.table, strings.Join(text, "\t"))
+		return err
+	case OutputCSV:
+		if !p.header {
+			p.header = true
+			if err := p.csv.Write(p.schema.columns); err != nil {
+				return err
+			}
+		}
+		return p.csv.Write(text)
+	case OutputNDJSON:
+		return p.addNDJSON(values)
+	case OutputYAML:
+		return p.addYAML(values)
+	case OutputTemplate:
+		return p.addTemplate(values)
+	}
+	if p.schema.simpleFormat != "" {
+		simple := make([]interface{}, len(p.simple))
+		for i, idx := range p.simple {
+			simple[i] = text[idx]
+		}
+		_, err := fmt.Fprintf(p.w, p.schema.simpleFormat+"\n", simple...)
+		return err
+	}
+	simple := make([]string, len(p.simple))
+	for i, idx := range p.simple {
+		simple[i] = text[idx]
+	}
+	_, err := fmt.Fprintln(p.w, strings.Join(simple, "\t"))
+	return err
+}
+
+// addTemplate execute template with a record(map of column to value)
+func (p *printer) addTemplate(values []interface{}) error {
+	if p.tmpl == nil {
+		return fmt.Errorf("template output requires a template")
+	}
+	record := make(map[string]interface{}, len(values))
+	for i, v := range values {
+		record[p.schema.columns[i]] = v
+	}
+	return p.tmpl.Execute(p.w, record)
+}
+
+// addNDJSON print a record as a JSON object(keys in order of schema columns) per line
+func (p *printer) addNDJSON(values []interface{}) error {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, v := range values {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(p.schema.columns[i])
+		val, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteString("}\n")
+	_, err := p.w.Write(buf.Bytes())
+	return err
+}
+
+// addYAML append a record as a YAML mapping(keys in order of schema columns)
+func (p *printer) addYAML(values []interface{}) error {
+	m := &yaml.Node{Kind: yaml.MappingNode}
+	for i, v := range values {
+		val := &yaml.Node{}
+		if err := val.Encode(v); err != nil {
+			return err
+		}
+		m.Content = append(m.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: p.schema.columns[i]}, val)
+	}
+	p.yaml.Content = append(p.yaml.Content, m)
+	return nil
+}
+
+// flush print buffered records
+func (p *printer) flush() error {
+	switch p.format {
+	case OutputCSV:
+		if !p.header {
+			p.header = true
+			if err := p.csv.Write(p.schema.columns); err != nil {
+				return err
+			}
+		}
+		p.csv.Flush()
+		return p.csv.Error()
+	case OutputTable:
+		return p.table.Flush()
+	case OutputYAML:
+		enc := yaml.NewEncoder(p.w)
+		enc.SetIndent(2)
+		if err := enc.Encode(p.yaml); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return nil
+}
+
+// printTransfer print the record of an uploaded, downloaded or copied Object,
+// nothing is printed in simple output
+func (sc *S3Cli) printTransfer(action, etag, key string) error {
+	if !sc.lineOutput() && !sc.recordOutput() {
+		return nil
+	}
+	p := sc.newPrinter(putSchema)
+	if err := p.add(time.Now(), action, etag, key); err != nil {
+		return err
+	}
+	return p.flush()
+}
+
+// granteeName return the display name(or ID, URI, email) of grantee
+func granteeName(g *s3.Grantee) string {
+	if g == nil {
+		return ""
+	}
+	for _, v := range []*string{g.DisplayName, g.ID, g.URI, g.EmailAddress} {
+		if aws.StringValue(v) != "" {
+			return aws.StringValue(v)
+		}
+	}
+	return ""
+}
+
+// printGrants print the grants of an ACL as records
+func (sc *S3Cli) printGrants(grants []*s3.Grant) error {
+	p := sc.newPrinter(aclSchema)
+	for _, g := range grants {
+		var kind string
+		if g.Grantee != nil {
+			kind = aws.StringValue(g.Grantee.Type)
+		}
+		if err := p.add(granteeName(g.Grantee), kind, aws.StringValue(g.Permission)); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// ownerName return the display name of owner
+func ownerName(o *s3.Owner) string {
+	if o == nil {
+		return ""
+	}
+	return aws.StringValue(o.DisplayName)
+}
+
+// printObjects print listed CommonPrefixes and Objects as records, records are numbered if index,
+// CommonPrefixes are records with only Key, they are skipped in line output
+func (sc *S3Cli) printObjects(prefixes []*s3.CommonPrefix, objects []*s3.Object, index bool) error {
+	s := objectSchema
+	if index {
+		s = indexedObjectSchema
+	}
+	p := sc.newPrinter(s)
+	for _, prefix := range prefixes {
+		if sc.lineOutput() {
+			continue
+		}
+		values := []interface{}{nil, nil, nil, nil, nil, aws.StringValue(prefix.Prefix)}
+		if index {
+			values = append([]interface{}{nil}, values...)
+		}
+		if err := p.add(values...); err != nil {
+			return err
+		}
+	}
+	for i, obj := range objects {
+		values := []interface{}{
+			aws.StringValue(obj.StorageClass),
+			aws.TimeValue(obj.LastModified),
+			aws.StringValue(obj.ETag),
+			aws.Int64Value(obj.Size),
+			ownerName(obj.Owner),
+			aws.StringValue(obj.Key),
+		}
+		if index {
+			values = append([]interface{}{int64(i)}, values...)
+		}
+		if err := p.add(values...); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
@@ -0,0 +1,261 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"gopkg.in/yaml.v3"
+)
+
+func Test_printer(t *testing.T) {
+	created := time.Date(2022, 5, 1, 8, 0, 0, 0, time.UTC)
+	cases := map[string]string{
+		OutputSimple: "bucket1\nbucket2\n",
+		OutputLine:   "2022-05-01T08:00:00Z owner bucket1\n2022-05-01T08:00:00Z owner bucket2\n",
+		OutputCSV:    "CreationDate,Owner,Name\n2022-05-01T08:00:00Z,owner,bucket1\n2022-05-01T08:00:00Z,owner,bucket2\n",
+		OutputTable:  "CREATIONDATE          OWNER  NAME\n2022-05-01T08:00:00Z  owner  bucket1\n2022-05-01T08:00:00Z  owner  bucket2\n",
+		OutputNDJSON: `{"CreationDate":"2022-05-01T08:00:00Z","Owner":"owner","Name":"bucket1"}` + "\n" +
+			`{"CreationDate":"2022-05-01T08:00:00Z","Owner":"owner","Name":"bucket2"}` + "\n",
+	}
+	for format, expect := range cases {
+		buf := &bytes.Buffer{}
+		p := newPrinter(format, bucketSchema, buf)
+		for _, name := range []string{"bucket1", "bucket2"} {
+			if err := p.add(created, "owner", name); err != nil {
+				t.Fatalf("%s add failed: %s", format, err)
+			}
+		}
+		if err := p.flush(); err != nil {
+			t.Fatalf("%s flush failed: %s", format, err)
+		}
+		if buf.String() != expect {
+			t.Errorf("%s output expect:\n%s\ngot:\n%s", format, expect, buf.String())
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	p := newPrinter(OutputYAML, bucketSchema, buf)
+	p.add(created, "owner", "bucket1")
+	if err := p.flush(); err != nil {
+		t.Fatal("yaml flush failed: ", err)
+	}
+	records := []map[string]interface{}{}
+	if err := yaml.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("invalid yaml output %s: %s", buf.String(), err)
+	}
+	if len(records) != 1 || records[0]["Name"] != "bucket1" || !strings.HasPrefix(buf.String(), "- CreationDate:") {
+		t.Errorf("unexpected yaml output %s", buf.String())
+	}
+
+	if err := newPrinter(OutputCSV, bucketSchema, buf).add("bucket1"); err == nil {
+		t.Errorf("record not match schema expect error")
+	}
+	if err := validOutput("xml"); err == nil {
+		t.Errorf("invalid output format expect error")
+	}
+}
+
+func Test_listObjectsOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	sc := s3cliTest
+	sc.Writer = buf
+	sc.Output = OutputNDJSON
+	out, err := sc.ListObjects(context.Background(), testBucketName, testObjectKey, "", "", 0, time.Time{}, time.Now().Add(time.Hour))
+	if err := sc.RenderObjects(out, false, err); err != nil {
+		t.Fatal("listObjects failed: ", err)
+	}
+	record := map[string]interface{}{}
+	line := strings.SplitN(buf.String(), "\n", 2)[0]
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		t.Fatalf("invalid ndjson output %s: %s", buf.String(), err)
+	}
+	for _, c := range objectSchema.columns {
+		if _, ok := record[c]; !ok {
+			t.Errorf("ndjson record expect column %s: %s", c, line)
+		}
+	}
+	if record["Key"] != testObjectKey || record["Size"] != float64(len(testObjectContent)) {
+		t.Errorf("unexpected ndjson record %s", line)
+	}
+
+	buf.Reset()
+	sc.Output = OutputSimple
+	head, err := sc.HeadObject(context.Background(), testBucketName, testObjectKey)
+	if err := sc.RenderHeadObject(testObjectKey, HeadOptions{}, head, err); err != nil {
+		t.Fatal("headObject failed: ", err)
+	}
+	if fields := strings.Split(strings.TrimRight(buf.String(), "\n"), "\t"); len(fields) != 2 || fields[0] != strconv.Itoa(len(testObjectContent)) {
+		t.Errorf("head simple output expect size and last-modified, got %s", buf.String())
+	}
+}
+
+func Test_setOutputFilter(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	sc := s3cliTest
+	sc.Writer = buf
+	sc.Output = OutputSimple
+	if err := sc.SetOutput("Contents[?Size>`1`].Key", "", false); err != nil {
+		t.Fatal("setOutput failed: ", err)
+	}
+	if !sc.jsonOutput() {
+		t.Errorf("filter expect json output, got %s", sc.Output)
+	}
+	out, err := sc.ListObjects(context.Background(), testBucketName, testObjectKey, "", "", 0, time.Time{}, time.Now())
+	if err := sc.RenderObjects(out, false, err); err != nil {
+		t.Fatal("listObjects failed: ", err)
+	}
+	keys := []string{}
+	if err := json.Unmarshal(buf.Bytes(), &keys); err != nil {
+		t.Fatalf("invalid filtered output %s: %s", buf.String(), err)
+	}
+	if len(keys) != 1 || keys[0] != testObjectKey {
+		t.Errorf("filtered output expect [%s], got %s", testObjectKey, buf.String())
+	}
+
+	sc.Output = OutputLine
+	if err := sc.SetOutput("Contents", "", true); err == nil {
+		t.Errorf("filter with line output expect error")
+	}
+	sc.Output = OutputJSON
+	if err := sc.SetOutput("Contents[?", "", false); err == nil {
+		t.Errorf("invalid filter expect error")
+	}
+
+	// runtime JMESPath error is returned
+	if err := sc.SetOutput("abs(Name)", "", false); err != nil {
+		t.Fatal("setOutput failed: ", err)
+	}
+	buf.Reset()
+	if err := sc.RenderObjects(out, false, nil); err == nil {
+		t.Errorf("filter abs(Name) expect error, got %s", buf.String())
+	}
+}
+
+// pagedClient is a s3iface.S3API that return every Object of ListObjectsV2 in its own page
+type pagedClient struct {
+	s3iface.S3API
+}
+
+func (c *pagedClient) ListObjectsV2PagesWithContext(ctx aws.Context, in *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool, opts ...request.Option) error {
+	out, err := c.S3API.ListObjectsV2WithContext(ctx, in, opts...)
+	if err != nil {
+		return err
+	}
+	for i, obj := range out.Contents {
+		last := i == len(out.Contents)-1
+		page := &s3.ListObjectsV2Output{Name: out.Name, Prefix: out.Prefix, KeyCount: aws.Int64(1), IsTruncated: aws.Bool(!last), Contents: []*s3.Object{obj}}
+		if !fn(page, last) {
+			break
+		}
+	}
+	return nil
+}
+
+func Test_listAllObjectsJSON(t *testing.T) {
+	for _, k := range []string{"paged/a", "paged/b", "paged/c"} {
+		if _, err := s3Backend.PutObject(testBucketName, k, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent))); err != nil {
+			t.Fatal("backend PutObject failed: ", err)
+		}
+	}
+	buf := &bytes.Buffer{}
+	sc := s3cliTest
+	sc.Client = &pagedClient{S3API: s3cliTest.Client}
+	sc.Writer = buf
+	sc.Output = OutputJSON
+	if err := sc.SetOutput("Contents[].Key", "", false); err != nil {
+		t.Fatal("setOutput failed: ", err)
+	}
+	out, err := sc.ListAllObjectsV2(context.Background(), testBucketName, "paged/", "", false, time.Time{}, time.Now())
+	if err := sc.RenderObjectsV2(out, false, err); err != nil {
+		t.Fatal("listAllObjectsV2 failed: ", err)
+	}
+	keys := []string{}
+	if err := json.Unmarshal(buf.Bytes(), &keys); err != nil {
+		t.Fatalf("pages expect one JSON document, got %s: %s", buf.String(), err)
+	}
+	if strings.Join(keys, ",") != "paged/a,paged/b,paged/c" {
+		t.Errorf("filtered pages expect all keys, got %v", keys)
+	}
+}
+
+// failWriter is a io.Writer always fail
+type failWriter struct{}
+
+func (failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func Test_outputError(t *testing.T) {
+	sc := s3cliTest
+	sc.Writer = &bytes.Buffer{}
+	sc.Output = OutputJSON
+	if err := sc.printJSON(make(chan int)); err == nil {
+		t.Errorf("printJSON unsupported type expect error")
+	}
+
+	// print error is returned
+	sc.Client = &pagedClient{S3API: s3cliTest.Client}
+	sc.Writer = failWriter{}
+	sc.Output = OutputLine
+	out, err := sc.ListAllObjectsV2(context.Background(), testBucketName, "", "", false, time.Time{}, time.Now())
+	if err != nil {
+		t.Fatal("listAllObjectsV2 failed: ", err)
+	}
+	if err := sc.RenderObjectsV2(out, false, nil); err == nil {
+		t.Errorf("renderObjectsV2 print error expect error")
+	}
+}
+
+func Test_recordOutputSchema(t *testing.T) {
+	ctx := context.Background()
+	cases := []struct {
+		name   string
+		schema schema
+		run    func(sc *S3Cli) error
+	}{
+		{"head bucket", bucketHeadSchema, func(sc *S3Cli) error {
+			out, err := sc.BucketHead(ctx, testBucketName)
+			return sc.RenderBucketHead(testBucketName, out, err)
+		}},
+		{"get versioning", versioningSchema, func(sc *S3Cli) error {
+			return sc.RenderBucketVersioning(sc.BucketVersioningGet(ctx, testBucketName))
+		}},
+		{"copy", putSchema, func(sc *S3Cli) error {
+			return sc.RenderCopy(sc.CopyObject(ctx, testBucketName+"/"+testObjectKey, testBucketName, "record/copy", "", nil, 0, 1, ObjectOptions{}))
+		}},
+		{"rename", renameSchema, func(sc *S3Cli) error {
+			return sc.RenderRename(sc.RenameObject(ctx, testBucketName+"/record/copy", testBucketName, "record/rename", 1))
+		}},
+		{"delete", deleteSchema, func(sc *S3Cli) error {
+			return sc.RenderDeleted(sc.DeleteObject(ctx, testBucketName, "record/rename", false))
+		}},
+		{"mpu create", mpuCreateSchema, func(sc *S3Cli) error {
+			return sc.RenderMPUCreate(sc.MPUCreate(ctx, testBucketName, "record/mpu"))
+		}},
+	}
+	for _, c := range cases {
+		buf := &bytes.Buffer{}
+		sc := s3cliTest
+		sc.Writer = buf
+		sc.Output = OutputCSV
+		if err := c.run(&sc); err != nil {
+			t.Fatalf("%s failed: %s", c.name, err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if lines[0] != strings.Join(c.schema.columns, ",") || len(lines) != 2 {
+			t.Errorf("%s csv output expect header %v and a record, got %s", c.name, c.schema.columns, buf.String())
+		}
+	}
+}
@@ -0,0 +1,776 @@
+package s3cli
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// render print results with print unless they are nil(e.g. presigned or failed before any result),
+// the error of the operation is returned in preference to the print error
+func render(isNil bool, err error, print func() error) error {
+	if isNil {
+		return err
+	}
+	if perr := print(); err == nil {
+		return perr
+	}
+	return err
+}
+
+// RenderBucketCreate print the created Buckets of BucketCreate, nothing printed in simple output
+func (sc *S3Cli) RenderBucketCreate(created []*s3.CreateBucketOutput, err error) error {
+	return render(created == nil, err, func() error {
+		if sc.verboseOutput() {
+			for _, c := range created {
+				fmt.Fprintln(sc.out(), c)
+			}
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(created)
+		} else if !sc.lineOutput() && !sc.recordOutput() {
+			return nil
+		}
+		p := sc.newPrinter(bucketCreateSchema)
+		for _, c := range created {
+			if err := p.add(aws.StringValue(c.Location)); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderDeleted print the deleted Objects(versions) of DeleteObject, DeleteObjects, DeletePrefix,
+// DeleteObjectVersion and DeleteBucketAndObjects, nothing printed in simple output
+func (sc *S3Cli) RenderDeleted(deleted []*s3.DeletedObject, err error) error {
+	return render(deleted == nil, err, func() error {
+		if sc.verboseOutput() {
+			for _, d := range deleted {
+				fmt.Fprintln(sc.out(), d)
+			}
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(deleted)
+		} else if !sc.lineOutput() && !sc.recordOutput() {
+			return nil
+		}
+		p := sc.newPrinter(deleteSchema)
+		for _, d := range deleted {
+			if err := p.add(aws.StringValue(d.Key), aws.StringValue(d.VersionId), aws.BoolValue(d.DeleteMarker)); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderCopy print the destination Object of CopyObject and CopyObjectFrom, nothing printed in simple output
+func (sc *S3Cli) RenderCopy(result *CopyResult, err error) error {
+	return render(result == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintf(sc.out(), "%+v\n", *result)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(result)
+		}
+		return sc.printTransfer("copy", result.ETag, result.Key)
+	})
+}
+
+// RenderRename print the results of RenameObject
+func (sc *S3Cli) RenderRename(results []RenameResult, err error) error {
+	return render(results == nil, err, func() error {
+		if sc.verboseOutput() {
+			for _, r := range results {
+				fmt.Fprintln(sc.out(), r.Source, r.Target, r.Error)
+			}
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(results)
+		}
+		p := sc.newPrinter(renameSchema)
+		for _, r := range results {
+			if err := p.add(r.Source, r.Target, r.Error); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderUpload print the results of UploadDir
+func (sc *S3Cli) RenderUpload(results []UploadResult, err error) error {
+	return render(results == nil, err, func() error {
+		if sc.verboseOutput() {
+			for _, r := range results {
+				fmt.Fprintln(sc.out(), r.File, r.Key, r.ETag, r.Error)
+			}
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(results)
+		}
+		p := sc.newPrinter(uploadResultSchema)
+		for _, r := range results {
+			if err := p.add(r.File, r.Key, r.ETag, r.Error); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderDownload print the results of DownloadDir
+func (sc *S3Cli) RenderDownload(results []DownloadResult, err error) error {
+	return render(results == nil, err, func() error {
+		if sc.verboseOutput() {
+			for _, r := range results {
+				fmt.Fprintln(sc.out(), r.Key, r.File, r.Size, r.Skipped, r.Error)
+			}
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(results)
+		}
+		p := sc.newPrinter(downloadResultSchema)
+		for _, r := range results {
+			if err := p.add(r.Key, r.File, r.Size, r.Skipped, r.Error); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderSync print the actions(and their results if not dry-run) of SyncDir
+func (sc *S3Cli) RenderSync(actions []SyncAction, err error) error {
+	return render(actions == nil, err, func() error {
+		if sc.verboseOutput() {
+			for _, a := range actions {
+				fmt.Fprintln(sc.out(), a.Action, a.Source, a.Target, a.Size, a.DryRun, a.Error)
+			}
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(actions)
+		}
+		p := sc.newPrinter(syncSchema)
+		for _, a := range actions {
+			if err := p.add(a.Action, a.Source, a.Target, a.Size, a.DryRun, a.Error); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketList print the Buckets of BucketList
+func (sc *S3Cli) RenderBucketList(out *s3.ListBucketsOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(bucketSchema)
+		for _, b := range out.Buckets {
+			if err := p.add(aws.TimeValue(b.CreationDate), ownerName(out.Owner), aws.StringValue(b.Name)); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketHead print the status of bucket headed by BucketHead
+func (sc *S3Cli) RenderBucketHead(bucket string, out *s3.HeadBucketOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(bucketHeadSchema)
+		if err := p.add(bucket, "ok"); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketEncryption print the encryption rules of BucketEncryptionGet
+func (sc *S3Cli) RenderBucketEncryption(out *s3.GetBucketEncryptionOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(encryptionSchema)
+		if c := out.ServerSideEncryptionConfiguration; c != nil {
+			for _, r := range c.Rules {
+				if r == nil {
+					continue
+				}
+				var algorithm, keyID string
+				if d := r.ApplyServerSideEncryptionByDefault; d != nil {
+					algorithm, keyID = aws.StringValue(d.SSEAlgorithm), aws.StringValue(d.KMSMasterKeyID)
+				}
+				if err := p.add(algorithm, keyID, aws.BoolValue(r.BucketKeyEnabled)); err != nil {
+					return err
+				}
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketACL print the grants of BucketACLGet
+func (sc *S3Cli) RenderBucketACL(out *s3.GetBucketAclOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printGrants(out.Grants)
+	})
+}
+
+// RenderObjectACL print the grants of GetObjectACL
+func (sc *S3Cli) RenderObjectACL(out *s3.GetObjectAclOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printGrants(out.Grants)
+	})
+}
+
+// RenderBucketPolicy print the policy of BucketPolicyGet
+func (sc *S3Cli) RenderBucketPolicy(out *s3.GetBucketPolicyOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(policySchema)
+		if err := p.add(aws.StringValue(out.Policy)); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketVersioning print the versioning status of BucketVersioningGet
+func (sc *S3Cli) RenderBucketVersioning(out *s3.GetBucketVersioningOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(versioningSchema)
+		if err := p.add(aws.StringValue(out.Status), aws.StringValue(out.MFADelete)); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketCors print the CORS rules of GetBucketCors
+func (sc *S3Cli) RenderBucketCors(out *s3.GetBucketCorsOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(corsSchema)
+		for _, r := range out.CORSRules {
+			if r == nil {
+				continue
+			}
+			if err := p.add(
+				aws.StringValue(r.ID),
+				strings.Join(aws.StringValueSlice(r.AllowedMethods), ","),
+				strings.Join(aws.StringValueSlice(r.AllowedOrigins), ","),
+				strings.Join(aws.StringValueSlice(r.AllowedHeaders), ","),
+				strings.Join(aws.StringValueSlice(r.ExposeHeaders), ","),
+				aws.Int64Value(r.MaxAgeSeconds),
+			); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderHeadObject print the Object(key) headed by HeadObject,
+// only its mtime, mtimestamp or x-amz-replication-status if specified in opt
+func (sc *S3Cli) RenderHeadObject(key string, opt HeadOptions, out *s3.HeadObjectOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if opt.Mtime {
+			fmt.Fprintln(sc.out(), out.LastModified)
+		} else if opt.Mtimestamp {
+			fmt.Fprintln(sc.out(), out.LastModified.Unix())
+		} else if opt.ReplicationStatus {
+			fmt.Fprintln(sc.out(), aws.StringValue(out.ReplicationStatus))
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		} else if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+		} else {
+			p := sc.newPrinter(headSchema)
+			if err := p.add(
+				key,
+				aws.Int64Value(out.ContentLength),
+				aws.TimeValue(out.LastModified),
+				aws.StringValue(out.ETag),
+				aws.StringValue(out.ContentType),
+				aws.StringValue(out.StorageClass),
+				aws.StringValue(out.VersionId),
+			); err != nil {
+				return err
+			}
+			return p.flush()
+		}
+		return nil
+	})
+}
+
+// RenderObjectLockConfig print the Object Lock configuration of GetObjectLockConfig
+func (sc *S3Cli) RenderObjectLockConfig(out *s3.GetObjectLockConfigurationOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(objectLockSchema)
+		if c := out.ObjectLockConfiguration; c != nil {
+			var mode string
+			var days, years interface{}
+			if c.Rule != nil && c.Rule.DefaultRetention != nil {
+				r := c.Rule.DefaultRetention
+				mode = aws.StringValue(r.Mode)
+				if r.Days != nil {
+					days = aws.Int64Value(r.Days)
+				}
+				if r.Years != nil {
+					years = aws.Int64Value(r.Years)
+				}
+			}
+			if err := p.add(aws.StringValue(c.ObjectLockEnabled), mode, days, years); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderObjects print the CommonPrefixes and Objects of ListAllObjects and ListObjects,
+// records are numbered if index
+func (sc *S3Cli) RenderObjects(out *s3.ListObjectsOutput, index bool, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printObjects(out.CommonPrefixes, out.Contents, index)
+	})
+}
+
+// RenderObjectsV2 print the CommonPrefixes and Objects of ListAllObjectsV2 and ListObjectsV2,
+// records are numbered if index
+func (sc *S3Cli) RenderObjectsV2(out *s3.ListObjectsV2Output, index bool, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printObjects(out.CommonPrefixes, out.Contents, index)
+	})
+}
+
+// RenderObjectVersions print the versions and delete markers of ListObjectVersions
+func (sc *S3Cli) RenderObjectVersions(out *s3.ListObjectVersionsOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.jsonOutput() {
+			return sc.printJSON(out)
+		} else if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		}
+		p := sc.newPrinter(versionSchema)
+		for _, v := range out.Versions {
+			if err := p.add(
+				aws.TimeValue(v.LastModified),
+				aws.StringValue(v.VersionId),
+				aws.BoolValue(v.IsLatest),
+				false,
+				aws.StringValue(v.ETag),
+				aws.Int64Value(v.Size),
+				aws.StringValue(v.Key),
+			); err != nil {
+				return err
+			}
+		}
+		for _, v := range out.DeleteMarkers {
+			if err := p.add(
+				aws.TimeValue(v.LastModified),
+				aws.StringValue(v.VersionId),
+				aws.BoolValue(v.IsLatest),
+				true,
+				nil,
+				nil,
+				aws.StringValue(v.Key),
+			); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderGet print the downloaded Object of GetObject and GetObjectRanged, nothing printed in simple output
+func (sc *S3Cli) RenderGet(result *GetResult, err error) error {
+	return render(result == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintf(sc.out(), "%+v\n", *result)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(result)
+		}
+		return sc.printTransfer("download", result.ETag, result.File)
+	})
+}
+
+// RenderMPUCreate print the UploadId of MPUCreate
+func (sc *S3Cli) RenderMPUCreate(out *s3.CreateMultipartUploadOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		p := sc.newPrinter(mpuCreateSchema)
+		if err := p.add(aws.StringValue(out.Bucket), aws.StringValue(out.Key), aws.StringValue(out.UploadId)); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderPartUpload print the results of MPUUpload and MPUUploadFile, failed parts are printed to stderr,
+// simple output is a part manifest(part-num:etag) of uploaded parts that mpu-complete accepts
+func (sc *S3Cli) RenderPartUpload(results []PartUploadResult, err error) error {
+	return render(results == nil, err, func() error {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(os.Stderr, "part %d upload failed: %s\n", r.PartNumber, r.Error)
+			}
+		}
+		if sc.jsonOutput() {
+			return sc.printJSON(results)
+		} else if sc.verboseOutput() {
+			for _, r := range results {
+				if r.Error == "" {
+					fmt.Fprintln(sc.out(), r.PartNumber, r.Offset, r.Size, r.ETag)
+				}
+			}
+			return nil
+		}
+		p := sc.newPrinter(partUploadSchema)
+		for _, r := range results {
+			if r.Error != "" {
+				continue
+			}
+			if err := p.add(r.PartNumber, r.Offset, r.Size, r.ETag); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderMPUAbort print the output of MPUAbort, nothing printed unless verbose output
+func (sc *S3Cli) RenderMPUAbort(out *s3.AbortMultipartUploadOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+		}
+		return nil
+	})
+}
+
+// RenderUploads print the Multi-Part-Uploads of MPUList
+func (sc *S3Cli) RenderUploads(out *s3.ListMultipartUploadsOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.jsonOutput() {
+			return sc.printJSON(out)
+		} else if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		}
+		p := sc.newPrinter(uploadSchema)
+		for _, u := range out.Uploads {
+			if err := p.add(aws.TimeValue(u.Initiated), aws.StringValue(u.UploadId), aws.StringValue(u.Key)); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
+
+// RenderParts print the uploaded parts of MPUListParts,
+// simple output is a part manifest(part-num:etag) that mpu-complete accepts
+func (sc *S3Cli) RenderParts(parts []*s3.Part, err error) error {
+	return render(parts == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), parts)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(parts)
+		}
+		pr := sc.newPrinter(partSchema)
+		for _, p := range parts {
+			if err := pr.add(
+				aws.Int64Value(p.PartNumber),
+				aws.TimeValue(p.LastModified),
+				aws.StringValue(p.ETag),
+				aws.Int64Value(p.Size),
+			); err != nil {
+				return err
+			}
+		}
+		return pr.flush()
+	})
+}
+
+// RenderMPU print the completed Multi-Part-Upload of MPU, MPUResume, MPUComplete and MPUCompleteAuto
+func (sc *S3Cli) RenderMPU(result *MPUResult, err error) error {
+	return render(result == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintf(sc.out(), "%+v\n", *result)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(result)
+		}
+		var uploaded, reused interface{}
+		if result.Uploaded != nil {
+			uploaded = *result.Uploaded
+		}
+		if result.Reused != nil {
+			reused = *result.Reused
+		}
+		p := sc.newPrinter(mpuResultSchema)
+		if err := p.add(result.Location, result.UploadID, result.ETag, result.VersionID, uploaded, reused); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketLifecycle print the lifecycle rules of BucketLifecycleGet
+func (sc *S3Cli) RenderBucketLifecycle(out *s3.GetBucketLifecycleConfigurationOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printLifecycleRules(out.Rules)
+	})
+}
+
+// RenderBucketReplication print the replication rules of BucketReplicationGet
+func (sc *S3Cli) RenderBucketReplication(out *s3.GetBucketReplicationOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		} else if out.ReplicationConfiguration == nil {
+			return nil
+		}
+		return sc.printReplicationRules(out.ReplicationConfiguration.Rules)
+	})
+}
+
+// RenderBucketNotification print the notifications of BucketNotificationGet
+func (sc *S3Cli) RenderBucketNotification(cfg *s3.NotificationConfiguration, err error) error {
+	return render(cfg == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), cfg)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(cfg)
+		}
+		return sc.printNotifications(cfg)
+	})
+}
+
+// RenderBucketLogging print the server access logging of BucketLoggingGet,
+// nothing printed if logging is disabled
+func (sc *S3Cli) RenderBucketLogging(out *s3.GetBucketLoggingOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		l := out.LoggingEnabled
+		if l == nil {
+			return nil
+		}
+		p := sc.newPrinter(loggingSchema)
+		if err := p.add(aws.StringValue(l.TargetBucket), aws.StringValue(l.TargetPrefix)); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketWebsite print the website configuration of BucketWebsiteGet
+func (sc *S3Cli) RenderBucketWebsite(out *s3.GetBucketWebsiteOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		var index, errorDoc, redirect string
+		if out.IndexDocument != nil {
+			index = aws.StringValue(out.IndexDocument.Suffix)
+		}
+		if out.ErrorDocument != nil {
+			errorDoc = aws.StringValue(out.ErrorDocument.Key)
+		}
+		if r := out.RedirectAllRequestsTo; r != nil {
+			redirect = aws.StringValue(r.HostName)
+			if r.Protocol != nil {
+				redirect = aws.StringValue(r.Protocol) + "://" + redirect
+			}
+		}
+		p := sc.newPrinter(websiteSchema)
+		if err := p.add(index, errorDoc, redirect, len(out.RoutingRules)); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderBucketTagging print the tags of BucketTaggingGet
+func (sc *S3Cli) RenderBucketTagging(out *s3.GetBucketTaggingOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printTags(out.TagSet)
+	})
+}
+
+// RenderObjectTagging print the tags of ObjectTaggingGet
+func (sc *S3Cli) RenderObjectTagging(out *s3.GetObjectTaggingOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		}
+		return sc.printTags(out.TagSet)
+	})
+}
+
+// RenderObjectRetention print the retention of ObjectRetentionGet
+func (sc *S3Cli) RenderObjectRetention(out *s3.GetObjectRetentionOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		} else if out.Retention == nil {
+			return nil
+		}
+		p := sc.newPrinter(retentionSchema)
+		if err := p.add(aws.StringValue(out.Retention.Mode), out.Retention.RetainUntilDate); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderObjectLegalHold print the legal hold status of ObjectLegalHoldGet
+func (sc *S3Cli) RenderObjectLegalHold(out *s3.GetObjectLegalHoldOutput, err error) error {
+	return render(out == nil, err, func() error {
+		if sc.verboseOutput() {
+			fmt.Fprintln(sc.out(), out)
+			return nil
+		} else if sc.jsonOutput() {
+			return sc.printJSON(out)
+		} else if out.LegalHold == nil {
+			return nil
+		}
+		p := sc.newPrinter(legalHoldSchema)
+		if err := p.add(aws.StringValue(out.LegalHold.Status)); err != nil {
+			return err
+		}
+		return p.flush()
+	})
+}
+
+// RenderAliases print the aliases of AliasList(sorted by name)
+func (sc *S3Cli) RenderAliases(aliases map[string]AliasConfig, err error) error {
+	return render(aliases == nil, err, func() error {
+		if sc.jsonOutput() || sc.verboseOutput() {
+			return sc.printJSON(aliases)
+		}
+		names := make([]string, 0, len(aliases))
+		for name := range aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		p := sc.newPrinter(aliasSchema)
+		for _, name := range names {
+			a := aliases[name]
+			pathStyle := "default"
+			if a.PathStyle != nil {
+				pathStyle = strconv.FormatBool(*a.PathStyle)
+			}
+			if err := p.add(name, a.Endpoint, a.Region, a.SignatureVersion, pathStyle, a.AccessKey); err != nil {
+				return err
+			}
+		}
+		return p.flush()
+	})
+}
@@ -0,0 +1,59 @@
+package s3cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_render(t *testing.T) {
+	sc := s3cliTest
+	buf := &bytes.Buffer{}
+	sc.Writer = buf
+	sc.Output = OutputCSV
+
+	failed := errors.New("1 of 2 Objects rename failed")
+	results := []RenameResult{
+		{Source: "b/k1", Target: "b/n1"},
+		{Source: "b/k2", Target: "b/n2", Error: "denied"},
+	}
+	if err := sc.RenderRename(results, failed); err != failed {
+		t.Errorf("renderRename expect operation error, got %v", err)
+	}
+	if got, want := buf.String(), "Source,Target,Error\nb/k1,b/n1,\nb/k2,b/n2,denied\n"; got != want {
+		t.Errorf("renderRename expect %q, got %q", want, got)
+	}
+
+	buf.Reset()
+	if err := sc.RenderRename(nil, failed); err != failed {
+		t.Errorf("renderRename nil results expect operation error, got %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("renderRename nil results expect no output, got %q", buf.String())
+	}
+
+	buf.Reset()
+	sc.Output = OutputJSON
+	if err := sc.RenderSync([]SyncAction{{Action: syncActionUpload, Source: "a.txt", Target: "b/a.txt", Size: 1, DryRun: true}}, nil); err != nil {
+		t.Fatal("renderSync failed: ", err)
+	}
+	actions := []SyncAction{}
+	if err := json.Unmarshal(buf.Bytes(), &actions); err != nil {
+		t.Fatal("renderSync json unmarshal failed: ", err)
+	}
+	if len(actions) != 1 || !actions[0].DryRun || actions[0].Target != "b/a.txt" {
+		t.Errorf("renderSync unexpected json %s", buf.String())
+	}
+
+	// single Object mutation print nothing in simple output
+	buf.Reset()
+	sc.Output = OutputSimple
+	if err := sc.RenderCopy(&CopyResult{Bucket: "b", Key: "k", ETag: "etag"}, nil); err != nil {
+		t.Fatal("renderCopy failed: ", err)
+	}
+	if strings.TrimSpace(buf.String()) != "" {
+		t.Errorf("renderCopy simple output expect nothing, got %q", buf.String())
+	}
+}
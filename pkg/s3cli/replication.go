@@ -0,0 +1,248 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// maxReplicationRules is the max number of rules of a Bucket replication configuration
+const maxReplicationRules = 1000
+
+var replicationSchema = schema{
+	columns: []string{"ID", "Priority", "Status", "Filter", "DeleteMarker", "Destination"},
+	simple:  []string{"ID", "Status", "Destination"},
+}
+
+// LoadReplicationConfig load the replication configuration(JSON or YAML) of file
+func LoadReplicationConfig(filename string) (*s3.ReplicationConfiguration, error) {
+	cfg := &s3.ReplicationConfiguration{}
+	if err := decodeFile(filename, cfg); err != nil {
+		return nil, fmt.Errorf("load replication %s failed: %w", filename, err)
+	}
+	return cfg, nil
+}
+
+// validStatus check the Enabled/Disabled status of a replication rule item
+func validStatus(rule, item string, status *string) error {
+	switch aws.StringValue(status) {
+	case s3.ReplicationRuleStatusEnabled, s3.ReplicationRuleStatusDisabled:
+		return nil
+	}
+	return fmt.Errorf("rule %s: invalid %s %q(Enabled or Disabled)", rule, item, aws.StringValue(status))
+}
+
+// validateReplicationFilter check a replication rule filter has at most one of Prefix, Tag and And
+func validateReplicationFilter(rule string, f *s3.ReplicationRuleFilter) error {
+	n := 0
+	for _, set := range []bool{f.Prefix != nil, f.Tag != nil, f.And != nil} {
+		if set {
+			n++
+		}
+	}
+	if n > 1 {
+		return fmt.Errorf("rule %s: filter requires at most one of Prefix, Tag and And", rule)
+	}
+	if f.And != nil && f.And.Prefix == nil && len(f.And.Tags) == 0 {
+		return fmt.Errorf("rule %s: filter And is empty", rule)
+	}
+	return nil
+}
+
+// validateReplication check a replication configuration before put it,
+// rules with Filter(V2) require Priority(unique) and DeleteMarkerReplication,
+// rules with Prefix(V1) take neither, V1 and V2 rules are exclusive
+func validateReplication(cfg *s3.ReplicationConfiguration) error {
+	if cfg == nil {
+		return errors.New("no replication rule")
+	}
+	names, err := ruleNames("replication", len(cfg.Rules), maxReplicationRules, func(i int) (*string, bool) {
+		if r := cfg.Rules[i]; r != nil {
+			return r.ID, true
+		}
+		return nil, false
+	})
+	if err != nil {
+		return err
+	}
+	priorities := map[int64]string{}
+	v1, v2 := 0, 0
+	for i, r := range cfg.Rules {
+		name := names[i]
+		if err := validStatus(name, "Status", r.Status); err != nil {
+			return err
+		}
+		if r.Destination == nil || aws.StringValue(r.Destination.Bucket) == "" {
+			return fmt.Errorf("rule %s: no destination Bucket", name)
+		}
+		if class := r.Destination.StorageClass; class != nil {
+			valid := false
+			for _, v := range s3.StorageClass_Values() {
+				valid = valid || *class == v
+			}
+			if !valid {
+				return fmt.Errorf("rule %s: invalid destination storage class %q(%s)", name, *class, strings.Join(s3.StorageClass_Values(), ", "))
+			}
+		}
+
+		switch {
+		case r.Prefix != nil && r.Filter != nil:
+			return fmt.Errorf("rule %s: Prefix and Filter are exclusive", name)
+		case r.Prefix != nil:
+			v1++
+			if r.Priority != nil || r.DeleteMarkerReplication != nil {
+				return fmt.Errorf("rule %s: Priority and DeleteMarkerReplication require Filter instead of Prefix", name)
+			}
+		case r.Filter != nil:
+			v2++
+			if err := validateReplicationFilter(name, r.Filter); err != nil {
+				return err
+			}
+			if r.Priority == nil {
+				return fmt.Errorf("rule %s: rule with Filter requires Priority", name)
+			}
+			if aws.Int64Value(r.Priority) < 0 {
+				return fmt.Errorf("rule %s: Priority must not be negative", name)
+			}
+			if other, ok := priorities[*r.Priority]; ok {
+				return fmt.Errorf("rule %s: Priority %d is used by rule %s", name, *r.Priority, other)
+			}
+			priorities[*r.Priority] = name
+			if r.DeleteMarkerReplication == nil {
+				return fmt.Errorf("rule %s: rule with Filter requires DeleteMarkerReplication", name)
+			}
+			if err := validStatus(name, "DeleteMarkerReplication Status", r.DeleteMarkerReplication.Status); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("rule %s: requires Filter(or Prefix)", name)
+		}
+	}
+	if v1 > 0 && v2 > 0 {
+		return errors.New("rules with Prefix and rules with Filter are exclusive")
+	}
+	return cfg.Validate()
+}
+
+// replicationFilter format the Objects filter of a replication rule
+func replicationFilter(r *s3.ReplicationRule) string {
+	if r.Prefix != nil {
+		return strings.Join(filterItems(r.Prefix, nil), ",")
+	}
+	f := r.Filter
+	if f == nil {
+		return ""
+	}
+	prefix, tags := f.Prefix, []*s3.Tag{f.Tag}
+	if f.And != nil {
+		prefix, tags = f.And.Prefix, f.And.Tags
+	}
+	return strings.Join(filterItems(prefix, tags), ",")
+}
+
+// printReplicationRules print replication rules as records
+func (sc *S3Cli) printReplicationRules(rules []*s3.ReplicationRule) error {
+	p := sc.newPrinter(replicationSchema)
+	for _, r := range rules {
+		var priority, deleteMarker, destination interface{}
+		if r.Priority != nil {
+			priority = *r.Priority
+		}
+		if r.DeleteMarkerReplication != nil {
+			deleteMarker = aws.StringValue(r.DeleteMarkerReplication.Status)
+		}
+		if r.Destination != nil {
+			destination = aws.StringValue(r.Destination.Bucket)
+		}
+		if err := p.add(aws.StringValue(r.ID), priority, aws.StringValue(r.Status), replicationFilter(r), deleteMarker, destination); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// BucketReplicationGet get a Bucket's replication configuration
+func (sc *S3Cli) BucketReplicationGet(ctx context.Context, bucket string) (*s3.GetBucketReplicationOutput, error) {
+	req, resp := sc.Client.GetBucketReplicationRequest(&s3.GetBucketReplicationInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get replication failed: %w", err)
+	}
+	return resp, nil
+}
+
+// BucketReplicationPut validate and put(replace) a Bucket's replication configuration
+func (sc *S3Cli) BucketReplicationPut(ctx context.Context, bucket string, cfg *s3.ReplicationConfiguration) error {
+	if err := validateReplication(cfg); err != nil {
+		return err
+	}
+	req, resp := sc.Client.PutBucketReplicationRequest(&s3.PutBucketReplicationInput{
+		Bucket:                   aws.String(bucket),
+		ReplicationConfiguration: cfg,
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put replication failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(cfg); err != nil {
+			return err
+		}
+	} else if sc.lineOutput() || sc.recordOutput() {
+		return sc.printReplicationRules(cfg.Rules)
+	}
+	return nil
+}
+
+// BucketReplicationDelete delete a Bucket's replication configuration
+func (sc *S3Cli) BucketReplicationDelete(ctx context.Context, bucket string) error {
+	req, resp := sc.Client.DeleteBucketReplicationRequest(&s3.DeleteBucketReplicationInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("delete replication failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_loadReplicationConfig(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"replication.yaml": `
+role: arn:aws:iam::123456789012:role/replication
+rules:
+  - id: logs
+    status: Enabled
+    priority: 1
+    filter:
+      prefix: logs/
+    deleteMarkerReplication:
+      status: Enabled
+    destination:
+      bucket: arn:aws:s3:::bucket-dst
+  - id: tagged
+    status: Disabled
+    priority: 2
+    filter:
+      and:
+        prefix: data/
+        tags:
+          - key: replicate
+            value: "yes"
+    deleteMarkerReplication:
+      status: Disabled
+    destination:
+      bucket: arn:aws:s3:::bucket-dst
+      storageClass: STANDARD_IA
+`,
+		"replication.json": `{"Role": "arn:aws:iam::123456789012:role/replication", "Rules": [
+  {"ID": "logs", "Status": "Enabled", "Priority": 1, "Filter": {"Prefix": "logs/"},
+   "DeleteMarkerReplication": {"Status": "Enabled"}, "Destination": {"Bucket": "arn:aws:s3:::bucket-dst"}},
+  {"ID": "tagged", "Status": "Disabled", "Priority": 2,
+   "Filter": {"And": {"Prefix": "data/", "Tags": [{"Key": "replicate", "Value": "yes"}]}},
+   "DeleteMarkerReplication": {"Status": "Disabled"}, "Destination": {"Bucket": "arn:aws:s3:::bucket-dst", "StorageClass": "STANDARD_IA"}}
+]}`,
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg, err := LoadReplicationConfig(filename)
+		if err != nil {
+			t.Fatalf("LoadReplicationConfig %s failed: %s", name, err)
+		}
+		if err := validateReplication(cfg); err != nil {
+			t.Errorf("%s expect valid, got %s", name, err)
+		}
+		if len(cfg.Rules) != 2 || replicationFilter(cfg.Rules[0]) != "prefix=logs/" {
+			t.Fatalf("%s rules mismatch: %v", name, cfg.Rules)
+		}
+		if got := replicationFilter(cfg.Rules[1]); got != "prefix=data/,tag=replicate:yes" {
+			t.Errorf("%s unexpected filter %s", name, got)
+		}
+	}
+
+	filename := filepath.Join(dir, "typo.yaml")
+	if err := os.WriteFile(filename, []byte("rules:\n  - id: x\n    target: b\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadReplicationConfig(filename); err == nil {
+		t.Errorf("unknown field expect error")
+	}
+}
+
+func Test_validateReplication(t *testing.T) {
+	rule := func(id string, priority int64, f func(r *s3.ReplicationRule)) *s3.ReplicationRule {
+		r := &s3.ReplicationRule{
+			ID:                      aws.String(id),
+			Status:                  aws.String(s3.ReplicationRuleStatusEnabled),
+			Priority:                aws.Int64(priority),
+			Filter:                  &s3.ReplicationRuleFilter{Prefix: aws.String("")},
+			DeleteMarkerReplication: &s3.DeleteMarkerReplication{Status: aws.String(s3.DeleteMarkerReplicationStatusDisabled)},
+			Destination:             &s3.Destination{Bucket: aws.String("arn:aws:s3:::bucket-dst")},
+		}
+		f(r)
+		return r
+	}
+	v1 := func(r *s3.ReplicationRule) {
+		r.Filter, r.Priority, r.DeleteMarkerReplication = nil, nil, nil
+		r.Prefix = aws.String("v1/")
+	}
+	none := func(r *s3.ReplicationRule) {}
+
+	valid := map[string][]*s3.ReplicationRule{
+		"v2 rules": {rule("a", 1, none), rule("b", 2, none)},
+		"v1 rules": {rule("a", 0, v1), rule("b", 0, v1)},
+	}
+	for name, rules := range valid {
+		cfg := &s3.ReplicationConfiguration{Role: aws.String("role"), Rules: rules}
+		if err := validateReplication(cfg); err != nil {
+			t.Errorf("%s expect valid, got %s", name, err)
+		}
+	}
+
+	cases := map[string][]*s3.ReplicationRule{
+		"no rule":            nil,
+		"invalid status":     {rule("a", 1, func(r *s3.ReplicationRule) { r.Status = aws.String("enabled") })},
+		"duplicate id":       {rule("a", 1, none), rule("a", 2, none)},
+		"duplicate priority": {rule("a", 1, none), rule("b", 1, none)},
+		"no priority":        {rule("a", 1, func(r *s3.ReplicationRule) { r.Priority = nil })},
+		"no delete marker":   {rule("a", 1, func(r *s3.ReplicationRule) { r.DeleteMarkerReplication = nil })},
+		"invalid delete marker": {rule("a", 1, func(r *s3.ReplicationRule) {
+			r.DeleteMarkerReplication.Status = aws.String("On")
+		})},
+		"no destination":    {rule("a", 1, func(r *s3.ReplicationRule) { r.Destination.Bucket = nil })},
+		"storage class":     {rule("a", 1, func(r *s3.ReplicationRule) { r.Destination.StorageClass = aws.String("COLD") })},
+		"no filter":         {rule("a", 1, func(r *s3.ReplicationRule) { r.Filter = nil })},
+		"prefix and filter": {rule("a", 1, func(r *s3.ReplicationRule) { r.Prefix = aws.String("a/") })},
+		"filter prefix and tag": {rule("a", 1, func(r *s3.ReplicationRule) {
+			r.Filter.Tag = &s3.Tag{Key: aws.String("k"), Value: aws.String("v")}
+		})},
+		"v1 priority":    {rule("a", 1, func(r *s3.ReplicationRule) { v1(r); r.Priority = aws.Int64(1) })},
+		"v1 and v2 rule": {rule("a", 1, none), rule("b", 0, v1)},
+	}
+	for name, rules := range cases {
+		cfg := &s3.ReplicationConfiguration{Role: aws.String("role"), Rules: rules}
+		if err := validateReplication(cfg); err == nil {
+			t.Errorf("%s expect error", name)
+		}
+	}
+}
+
+func Test_headReplicationStatus(t *testing.T) {
+	sc, h := newHeaderRecorderCli(t, "bucket-replication")
+	ctx := context.Background()
+	if _, err := sc.PutObject(ctx, "bucket-replication", "key", "", nil, false, bytes.NewReader(testObjectContent), ObjectOptions{}); err != nil {
+		t.Fatal("putObject failed: ", err)
+	}
+	h.mu.Lock()
+	h.respHeaders = map[string][]string{"X-Amz-Replication-Status": {s3.ReplicationStatusComplete}}
+	h.mu.Unlock()
+
+	buf := &bytes.Buffer{}
+	sc.Writer = buf
+	out, err := sc.HeadObject(ctx, "bucket-replication", "key")
+	if err := sc.RenderHeadObject("key", HeadOptions{ReplicationStatus: true}, out, err); err != nil {
+		t.Fatal("headObject failed: ", err)
+	}
+	if aws.StringValue(out.ReplicationStatus) != s3.ReplicationStatusComplete {
+		t.Errorf("replication status expect %s, got %v", s3.ReplicationStatusComplete, out.ReplicationStatus)
+	}
+	if got := buf.String(); got != s3.ReplicationStatusComplete+"\n" {
+		t.Errorf("headObject --replication-status expect %s, got %q", s3.ReplicationStatusComplete, got)
+	}
+}
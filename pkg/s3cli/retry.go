@@ -0,0 +1,202 @@
+package s3cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	DefaultMaxRetries    = 3
+	DefaultRetryMinDelay = 200 * time.Millisecond
+	DefaultRetryMaxDelay = 20 * time.Second
+	// DefaultBodyBufferSize is the max size of a non-seekable body buffered to be retried
+	DefaultBodyBufferSize = s3manager.MinUploadPartSize
+
+	errCodeNotRetried = "NotRetried"
+)
+
+// throttleCodes is the error codes of S3 compatible services that mean throttling
+var throttleCodes = map[string]bool{
+	"SlowDown":                 true,
+	"Throttling":               true,
+	"ThrottlingException":      true,
+	"RequestLimitExceeded":     true,
+	"TooManyRequests":          true,
+	"RequestThrottled":         true,
+	"ServiceUnavailable":       true,
+	"TooManyRequestsException": true,
+}
+
+// retryer is a request.Retryer with exponential backoff and full jitter,
+// it retries throttling, 5xx and connection errors, honors Retry-After
+// and never retries a request whose body can't be rewound(e.g. stdin larger than the buffer)
+type retryer struct {
+	maxRetries int
+	minDelay   time.Duration
+	maxDelay   time.Duration
+	verbose    bool
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// newRetryer create a retryer, zero delay means the default one
+func newRetryer(maxRetries int, minDelay, maxDelay time.Duration, verbose bool) *retryer {
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	if minDelay <= 0 {
+		minDelay = DefaultRetryMinDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	if maxDelay < minDelay {
+		maxDelay = minDelay
+	}
+	return &retryer{
+		maxRetries: maxRetries,
+		minDelay:   minDelay,
+		maxDelay:   maxDelay,
+		verbose:    verbose,
+		rand:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// MaxRetries return the max number of retries
+func (r *retryer) MaxRetries() int {
+	return r.maxRetries
+}
+
+// ShouldRetry report whether a failed request should be retried
+func (r *retryer) ShouldRetry(req *request.Request) bool {
+	retry := req.IsErrorRetryable() || isThrottle(req)
+	if req.Retryable != nil {
+		retry = *req.Retryable
+	}
+	if retry && !bodyRewindable(req) {
+		req.Error = awserr.New(errCodeNotRetried, "not retried: body not rewindable", req.Error)
+		return false
+	}
+	return retry
+}
+
+// RetryRules return the delay before next retry
+func (r *retryer) RetryRules(req *request.Request) time.Duration {
+	// full jitter: random in [minDelay, min(maxDelay, minDelay*2^retryCount)]
+	ceil := r.maxDelay
+	if req.RetryCount < 30 {
+		if d := r.minDelay << uint(req.RetryCount); d > 0 && d < ceil {
+			ceil = d
+		}
+	}
+	r.mu.Lock()
+	delay := r.minDelay + time.Duration(r.rand.Int63n(int64(ceil-r.minDelay)+1))
+	r.mu.Unlock()
+	if after, ok := retryAfter(req.HTTPResponse); ok && after > delay {
+		delay = after
+	}
+	if delay > r.maxDelay {
+		delay = r.maxDelay
+	}
+
+	if r.verbose {
+		fmt.Fprintf(os.Stderr, "retry %d/%d %s %s after %s: %s\n",
+			req.RetryCount+1, r.maxRetries, req.Operation.Name, req.HTTPRequest.URL.Path, delay, req.Error)
+	}
+	return delay
+}
+
+// isThrottle report whether a request failed because of throttling
+func isThrottle(req *request.Request) bool {
+	if req.IsErrorThrottle() {
+		return true
+	}
+	if req.HTTPResponse != nil {
+		switch req.HTTPResponse.StatusCode {
+		case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+			return true
+		}
+	}
+	if e, ok := req.Error.(awserr.Error); ok {
+		return throttleCodes[e.Code()]
+	}
+	return false
+}
+
+// bodyRewindable report whether the request body can be read again
+func bodyRewindable(req *request.Request) bool {
+	if req.Body == nil {
+		return true
+	}
+	if !aws.IsReaderSeekable(req.Body) {
+		return false
+	}
+	// a pipe(e.g. stdin) is a *os.File but can't seek
+	_, err := req.Body.Seek(0, io.SeekCurrent)
+	return err == nil
+}
+
+// unseekableBody is a request body that can't seek(and can't be retried)
+type unseekableBody struct {
+	io.Reader
+}
+
+// Seek always fail
+func (unseekableBody) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("body not seekable")
+}
+
+// rewindableBody return r if it can seek, otherwise buffer r(at most limit bytes) in memory
+// so the request can be retried, a body larger than limit is returned unseekable
+func rewindableBody(r io.ReadSeeker, limit int64) (io.ReadSeeker, error) {
+	if _, err := r.Seek(0, io.SeekCurrent); err == nil {
+		return r, nil
+	}
+	if limit <= 0 {
+		limit = DefaultBodyBufferSize
+	}
+	buf := &bytes.Buffer{}
+	_, err := io.CopyN(buf, r, limit+1)
+	if err == io.EOF {
+		return bytes.NewReader(buf.Bytes()), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return unseekableBody{io.MultiReader(buf, r)}, nil
+}
+
+// retryAfter parse Retry-After header(seconds or http date)
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,206 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+// slowDownHandler reply the first n requests with 503 SlowDown
+type slowDownHandler struct {
+	n     int32
+	count int32
+	next  http.Handler
+}
+
+func (h *slowDownHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if atomic.AddInt32(&h.count, 1) <= h.n {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>`))
+		return
+	}
+	h.next.ServeHTTP(w, r)
+}
+
+func Test_retryer(t *testing.T) {
+	for _, c := range []struct {
+		maxRetries int
+		ok         bool
+		requests   int32
+	}{{3, true, 3}, {1, false, 2}} {
+		h := &slowDownHandler{n: 2, next: gofakes3.New(s3mem.New()).Server()}
+		ts := httptest.NewServer(h)
+		sc := &S3Cli{
+			Endpoint:      ts.URL,
+			AccessKey:     "my-ak",
+			SecretKey:     "my-sk",
+			Region:        s3.BucketLocationConstraintCnNorth1,
+			PathStyle:     true,
+			MaxRetries:    c.maxRetries,
+			RetryMinDelay: time.Millisecond,
+			RetryMaxDelay: 10 * time.Millisecond,
+		}
+		client, err := NewS3Client(sc)
+		if err != nil {
+			t.Fatal("newS3Client failed: ", err)
+		}
+		_, err = client.ListBucketsWithContext(context.Background(), &s3.ListBucketsInput{})
+		ts.Close()
+		if c.ok && err != nil {
+			t.Errorf("max-retries %d expect success, got %s", c.maxRetries, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("max-retries %d expect error", c.maxRetries)
+		}
+		if h.count != c.requests {
+			t.Errorf("max-retries %d expect %d requests, got %d", c.maxRetries, c.requests, h.count)
+		}
+	}
+}
+
+func Test_retryRules(t *testing.T) {
+	r := newRetryer(5, 10*time.Millisecond, time.Second, false)
+	for i := 0; i < 5; i++ {
+		req := &request.Request{
+			RetryCount:  i,
+			Operation:   &request.Operation{Name: "GetObject"},
+			HTTPRequest: httptest.NewRequest(http.MethodGet, "/bucket/key", nil),
+		}
+		d := r.RetryRules(req)
+		if d < 10*time.Millisecond || d > 10*time.Millisecond<<uint(i) {
+			t.Errorf("retry %d delay %s out of range", i, d)
+		}
+	}
+
+	resp := &http.Response{Header: http.Header{}}
+	resp.Header.Set("Retry-After", "3")
+	req := &request.Request{
+		Operation:    &request.Operation{Name: "GetObject"},
+		HTTPRequest:  httptest.NewRequest(http.MethodGet, "/bucket/key", nil),
+		HTTPResponse: resp,
+	}
+	if d := newRetryer(1, time.Millisecond, 5*time.Second, false).RetryRules(req); d != 3*time.Second {
+		t.Errorf("Retry-After 3 expect delay 3s, got %s", d)
+	}
+	if d := newRetryer(1, time.Millisecond, time.Second, false).RetryRules(req); d != time.Second {
+		t.Errorf("Retry-After 3 expect delay capped at 1s, got %s", d)
+	}
+}
+
+func Test_bodyRewindable(t *testing.T) {
+	if !bodyRewindable(&request.Request{}) {
+		t.Errorf("nil body should be rewindable")
+	}
+	if !bodyRewindable(&request.Request{Body: bytes.NewReader([]byte("data"))}) {
+		t.Errorf("bytes.Reader body should be rewindable")
+	}
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pr.Close()
+	defer pw.Close()
+	if bodyRewindable(&request.Request{Body: pr}) {
+		t.Errorf("pipe body should not be rewindable")
+	}
+	r := newRetryer(3, 0, 0, false)
+	if r.ShouldRetry(&request.Request{Body: pr}) {
+		t.Errorf("request with pipe body should not be retried")
+	}
+
+	// a retryable error of a body can't be rewound is surfaced
+	req := &request.Request{
+		Body:         unseekableBody{pr},
+		HTTPResponse: &http.Response{StatusCode: http.StatusServiceUnavailable},
+		Error:        awserr.New("SlowDown", "Please reduce your request rate.", nil),
+	}
+	if r.ShouldRetry(req) {
+		t.Errorf("request with unseekable body should not be retried")
+	}
+	if e, ok := req.Error.(awserr.Error); !ok || e.Code() != errCodeNotRetried {
+		t.Errorf("expect %s error, got %v", errCodeNotRetried, req.Error)
+	}
+}
+
+func Test_rewindableBody(t *testing.T) {
+	pipe := func(data string) *os.File {
+		pr, pw, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			pw.Write([]byte(data))
+			pw.Close()
+		}()
+		t.Cleanup(func() { pr.Close() })
+		return pr
+	}
+
+	body, err := rewindableBody(pipe("stdin data"), 16)
+	if err != nil {
+		t.Fatal("rewindableBody failed: ", err)
+	}
+	if !bodyRewindable(&request.Request{Body: body}) {
+		t.Errorf("pipe body smaller than buffer should be rewindable")
+	}
+
+	body, err = rewindableBody(pipe("stdin data larger than buffer"), 16)
+	if err != nil {
+		t.Fatal("rewindableBody failed: ", err)
+	}
+	if bodyRewindable(&request.Request{Body: body}) {
+		t.Errorf("pipe body larger than buffer should not be rewindable")
+	}
+	if data, _ := io.ReadAll(body); string(data) != "stdin data larger than buffer" {
+		t.Errorf("unseekable body expect all data, got %q", data)
+	}
+
+	// stdin upload is retried after 503 SlowDown
+	backend := s3mem.New()
+	if err := backend.CreateBucket("bucket-stdin"); err != nil {
+		t.Fatal(err)
+	}
+	h := &slowDownHandler{n: 1, next: gofakes3.New(backend).Server()}
+	ts := httptest.NewServer(h)
+	defer ts.Close()
+	sc := &S3Cli{
+		Endpoint:      ts.URL,
+		AccessKey:     "my-ak",
+		SecretKey:     "my-sk",
+		Region:        s3.BucketLocationConstraintCnNorth1,
+		PathStyle:     true,
+		MaxRetries:    2,
+		RetryMinDelay: time.Millisecond,
+		RetryMaxDelay: 10 * time.Millisecond,
+		Writer:        io.Discard,
+	}
+	if sc.Client, err = NewS3Client(sc); err != nil {
+		t.Fatal("newS3Client failed: ", err)
+	}
+	if _, err := sc.PutObject(context.Background(), "bucket-stdin", "key", "", nil, false, pipe("stdin data"), ObjectOptions{}); err != nil {
+		t.Fatal("putObject stdin failed: ", err)
+	}
+	if h.count != 2 {
+		t.Errorf("putObject stdin expect 2 requests, got %d", h.count)
+	}
+	obj, err := backend.GetObject("bucket-stdin", "key", nil)
+	if err != nil {
+		t.Fatal("backend GetObject failed: ", err)
+	}
+	defer obj.Contents.Close()
+	if data, _ := io.ReadAll(obj.Contents); string(data) != "stdin data" {
+		t.Errorf("putObject stdin expect %q, got %q", "stdin data", data)
+	}
+}
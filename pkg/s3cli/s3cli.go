@@ -0,0 +1,2888 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/jmespath/go-jmespath"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OutputVerbose = "verbose"
+	outputV       = "v"
+	OutputSimple  = "simple"
+	outputS       = "s"
+	OutputLine    = "line"
+	outputL       = "l"
+	OutputJSON    = "json"
+	outputJ       = "j"
+)
+
+const (
+	// maxCopyPartSize is the max part size of UploadPartCopy
+	maxCopyPartSize = 5 << 30
+	// DefaultCopyPartSize is the part size of multipart copy(UploadPartCopy)
+	DefaultCopyPartSize = 512 << 20
+)
+
+// maxCopyObjectSize is the max Object size of a single CopyObject request,
+// larger Objects are copied with UploadPartCopy
+var maxCopyObjectSize int64 = 5 << 30
+
+// S3Cli represent a S3Cli Client, exported fields are its settings,
+// command output is written to Writer(default os.Stdout)
+type S3Cli struct {
+	Profile      string // profile in credentials file
+	Endpoint     string // Server endpoine(URL)
+	AccessKey    string // access-key
+	SecretKey    string // secret-key
+	SessionToken string
+	Region       string
+	// With ForcePathStyle(PathStyle=true):
+	// 	https://s3.us-west-2.amazonaws.com/BUCKET/KEY
+	// Without ForcePathStyle(PathStyle=false):
+	// 	https://BUCKET.s3.us-west-2.amazonaws.com/KEY
+	PathStyle bool
+	V2Sign    bool   // S3 signature v2
+	Proxy     string // http proxy URL, empty to use proxy from env, "none" to disable proxy
+	NoProxy   bool   // not use proxy from env
+	// http dial and response header timeout in seconds
+	DialTimeout               int
+	ResponseHeaderTimeout     int
+	DisableKeepAlives         bool // disable http Keep-Alive
+	DisableContentMD5Validate bool // disable content md5 validate(header Content-Md5)
+	// retry policy
+	MaxRetries    int
+	RetryMinDelay time.Duration
+	RetryMaxDelay time.Duration
+	// max size of a non-seekable upload body(e.g. stdin) buffered in memory to be retried
+	BodyBufferSize int64
+	// TLS settings
+	Insecure      bool   // skip server certificate verification
+	CABundle      string // PEM file of extra CA certificates
+	ClientCert    string // PEM file of client certificate(mTLS)
+	ClientKey     string // PEM file of client key(mTLS)
+	TLSServerName string // server name to verify certificate
+	TLSMinVersion string // min TLS version(1.0, 1.1, 1.2 or 1.3)
+	Presign       bool   // just presign
+	PresignExp    time.Duration
+	Output        string    // output format
+	Writer        io.Writer // command output, default os.Stdout
+	Header        []string  // custom header(s)
+	Query         []string  // custom query
+	Debug         bool
+	Trace         bool               // print phase timings of every http request
+	filter        *jmespath.JMESPath // JMESPath expression applied to JSON output
+	tmpl          *template.Template // template of template output
+	Client        s3iface.S3API      // manual init this field(NewS3Client or a mock)
+}
+
+// out return the command output writer
+func (sc *S3Cli) out() io.Writer {
+	if sc.Writer == nil {
+		return os.Stdout
+	}
+	return sc.Writer
+}
+
+func (sc *S3Cli) SplitKeyValue(data, sep string) (string, string) {
+	bo := strings.SplitN(data, sep, 2)
+	if len(bo) == 2 {
+		return bo[0], bo[1]
+	}
+	return data, ""
+}
+
+// decodeFile decode a JSON or YAML file to v(SDK structure, keys are case-insensitive),
+// unknown keys are errors
+func decodeFile(filename string, v interface{}) error {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return err
+	}
+	var doc interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if data, err = json.Marshal(doc); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
+
+func (sc *S3Cli) addCustomHeader(req *http.Request) {
+	for _, h := range sc.Header {
+		hk, hv := sc.SplitKeyValue(h, ":")
+		req.Header.Add(hk, hv)
+	}
+
+	q := req.URL.Query()
+	for _, h := range sc.Query {
+		hk, hv := sc.SplitKeyValue(h, "=")
+		q.Add(hk, hv)
+	}
+	req.URL.RawQuery = q.Encode()
+}
+
+// PresignV2Raw presigne URL with raw(not escape) key(Object name).
+func (sc *S3Cli) PresignV2Raw(method, bucketKey, contentType string) (string, error) {
+	if bucketKey == "" || bucketKey[0] == '/' {
+		return "", fmt.Errorf("invalid bucket/key: %s", bucketKey)
+	}
+	creds := credentials.NewStaticCredentials(sc.AccessKey, sc.SecretKey, sc.SessionToken)
+	if c, ok := sc.Client.(*s3.S3); ok {
+		creds = c.Config.Credentials
+	}
+	secret, err := creds.Get()
+	if err != nil {
+		return "", fmt.Errorf("access/secret key, %w", err)
+	}
+
+	u, err := url.Parse(fmt.Sprintf("%s/%s", sc.Endpoint, bucketKey))
+	if err != nil {
+		return "", err
+	}
+	exp := strconv.FormatInt(time.Now().Unix()+int64(sc.PresignExp.Seconds()), 10)
+
+	q := u.Query()
+	q.Set("AWSAccessKeyId", secret.AccessKeyID)
+	q.Set("Expires", exp)
+
+	contentMd5 := "" // header Content-MD5
+	strToSign := fmt.Sprintf("%s\n%s\n%s\n%v\n%s", method, contentMd5, contentType, exp, u.EscapedPath())
+
+	mac := hmac.New(sha1.New, []byte(secret.SecretAccessKey))
+	mac.Write([]byte(strToSign))
+
+	q.Set("Signature", base64.StdEncoding.EncodeToString(mac.Sum(nil)))
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// ErrorHandler
+func (sc *S3Cli) ErrorHandler(err error) error {
+	if sc.verboseOutput() {
+		return err
+	}
+	if err != nil {
+		fmt.Fprintln(sc.out(), err)
+	}
+	return nil
+}
+
+func (sc *S3Cli) jsonOutput() (v bool) {
+	if sc.Output == OutputJSON || sc.Output == outputJ {
+		v = true
+	}
+	return
+}
+
+func (sc *S3Cli) verboseOutput() (v bool) {
+	if sc.Output == OutputVerbose || sc.Output == outputV {
+		v = true
+	}
+	return
+}
+
+func (sc *S3Cli) lineOutput() (v bool) {
+	if sc.Output == OutputLine || sc.Output == outputL {
+		v = true
+	}
+	return
+}
+
+func (sc *S3Cli) simpleOutput() (v bool) {
+	if sc.Output == OutputSimple || sc.Output == outputS {
+		v = true
+	}
+	return
+}
+
+// BucketCreate create Buckets, return the outputs of created Buckets(before the failed one)
+func (sc *S3Cli) BucketCreate(ctx context.Context, buckets []string) ([]*s3.CreateBucketOutput, error) {
+	created := make([]*s3.CreateBucketOutput, 0, len(buckets))
+	for _, b := range buckets {
+		createBucketInput := &s3.CreateBucketInput{
+			Bucket: aws.String(b),
+			CreateBucketConfiguration: &s3.CreateBucketConfiguration{
+				LocationConstraint: aws.String(sc.Region),
+			},
+		}
+
+		req, resp := sc.Client.CreateBucketRequest(createBucketInput)
+		req.SetContext(ctx)
+		if sc.Presign {
+			s, err := req.Presign(sc.PresignExp)
+			if err == nil {
+				fmt.Fprintln(sc.out(), s)
+			}
+			return nil, err
+		}
+
+		sc.addCustomHeader(req.HTTPRequest)
+		err := req.Send()
+		if err != nil {
+			return created, err
+		}
+		created = append(created, resp)
+	}
+	return created, nil
+}
+
+// BucketList list all my Buckets
+func (sc *S3Cli) BucketList(ctx context.Context) (*s3.ListBucketsOutput, error) {
+	req, resp := sc.Client.ListBucketsRequest(&s3.ListBucketsInput{})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BucketHead head a Bucket
+func (sc *S3Cli) BucketHead(ctx context.Context, bucket string) (*s3.HeadBucketOutput, error) {
+	req, resp := sc.Client.HeadBucketRequest(&s3.HeadBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// BucketEncryptionGet get a Bucket bucketEncryptionGet
+func (sc *S3Cli) BucketEncryptionGet(ctx context.Context, bucket string) (*s3.GetBucketEncryptionOutput, error) {
+	req, resp := sc.Client.GetBucketEncryptionRequest(&s3.GetBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// BucketEncryptionPut put a Bucket bucketEncryptionGet
+func (sc *S3Cli) BucketEncryptionPut(ctx context.Context, bucket, algorithm string) error {
+	req, resp := sc.Client.PutBucketEncryptionRequest(&s3.PutBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+		ServerSideEncryptionConfiguration: &s3.ServerSideEncryptionConfiguration{
+			Rules: []*s3.ServerSideEncryptionRule{
+				{
+					ApplyServerSideEncryptionByDefault: &s3.ServerSideEncryptionByDefault{
+						SSEAlgorithm: aws.String(algorithm),
+					},
+				},
+			},
+		},
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		return sc.printJSON(resp)
+	}
+	return nil
+}
+
+// BucketEncryptionDelete delete a Bucket bucketEncryption
+func (sc *S3Cli) BucketEncryptionDelete(ctx context.Context, bucket string) error {
+	req, resp := sc.Client.DeleteBucketEncryptionRequest(&s3.DeleteBucketEncryptionInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		return sc.printJSON(resp)
+	}
+	return nil
+}
+
+// BucketACLGet get a Bucket's ACL
+func (sc *S3Cli) BucketACLGet(ctx context.Context, bucket string) (*s3.GetBucketAclOutput, error) {
+	req, resp := sc.Client.GetBucketAclRequest(&s3.GetBucketAclInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BucketACLSet set a Bucket's ACL
+func (sc *S3Cli) BucketACLSet(ctx context.Context, bucket string, acl string) error {
+	req, resp := sc.Client.PutBucketAclRequest(&s3.PutBucketAclInput{
+		ACL:    aws.String(acl),
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// BucketPolicyGet get a Bucket's Policy
+func (sc *S3Cli) BucketPolicyGet(ctx context.Context, bucket string) (*s3.GetBucketPolicyOutput, error) {
+	req, resp := sc.Client.GetBucketPolicyRequest(&s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BucketPolicySet set a Bucket's Policy
+func (sc *S3Cli) BucketPolicySet(ctx context.Context, bucket, policy string) error {
+	if policy == "" {
+		return errors.New("empty policy")
+	}
+
+	req, resp := sc.Client.PutBucketPolicyRequest(&s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(policy),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// BucketVersioningGet get a Bucket's Versioning status
+func (sc *S3Cli) BucketVersioningGet(ctx context.Context, bucket string) (*s3.GetBucketVersioningOutput, error) {
+	req, resp := sc.Client.GetBucketVersioningRequest(&s3.GetBucketVersioningInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BucketVersioningSet set a Bucket's Versioning status
+func (sc *S3Cli) BucketVersioningSet(ctx context.Context, bucket string, status string) error {
+	req, resp := sc.Client.PutBucketVersioningRequest(&s3.PutBucketVersioningInput{
+		Bucket: aws.String(bucket),
+		VersioningConfiguration: &s3.VersioningConfiguration{
+			Status: aws.String(status),
+		},
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// bucketDelete delete a Bucket
+func (sc *S3Cli) bucketDelete(ctx context.Context, bucket string) error {
+	req, _ := sc.Client.DeleteBucketRequest(&s3.DeleteBucketInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	return err
+}
+
+func (sc *S3Cli) GetBucketCors(ctx context.Context, bucket string) (*s3.GetBucketCorsOutput, error) {
+	req, out := sc.Client.GetBucketCorsRequest(&s3.GetBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (sc *S3Cli) DeleteBucketCors(ctx context.Context, bucket string) error {
+	req, out := sc.Client.DeleteBucketCorsRequest(&s3.DeleteBucketCorsInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), out)
+	}
+	return nil
+}
+
+func (sc *S3Cli) PutBucketCors(ctx context.Context, bucket, cfgFile string) error {
+	fd, err := os.Open(cfgFile)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	corsCfg := s3.CORSConfiguration{}
+	err = json.NewDecoder(fd).Decode(&corsCfg)
+	if err != nil {
+		return err
+	}
+
+	req, out := sc.Client.PutBucketCorsRequest(&s3.PutBucketCorsInput{
+		Bucket:            aws.String(bucket),
+		CORSConfiguration: &corsCfg,
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err = req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), out)
+	}
+	return nil
+}
+
+// PutObject upload a Object
+func (sc *S3Cli) PutObject(ctx context.Context, bucket, key, contentType string, metadata map[string]*string, stream bool, r io.ReadSeeker, opt ObjectOptions) (*s3.PutObjectOutput, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	var objContentType *string
+	if contentType != "" {
+		objContentType = aws.String(contentType)
+	}
+
+	putObjectInput := &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		ContentType: objContentType,
+		Metadata:    metadata,
+		Tagging:     opt.tagging(),
+
+		ObjectLockMode:            opt.lockMode(),
+		ObjectLockRetainUntilDate: opt.lockRetainUntil(),
+		ObjectLockLegalHoldStatus: opt.legalHold(),
+		WebsiteRedirectLocation:   opt.websiteRedirect(),
+	}
+
+	if stream {
+		putObjectInput.ContentLength = aws.Int64(0)
+	}
+	if !reflect.ValueOf(r).IsNil() {
+		body, err := rewindableBody(r, sc.BodyBufferSize)
+		if err != nil {
+			return nil, err
+		}
+		putObjectInput.Body = body
+	}
+	req, resp := sc.Client.PutObjectRequest(putObjectInput)
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp.String())
+		return resp, nil
+	}
+	return resp, sc.printTransfer("upload", aws.StringValue(resp.ETag), key)
+}
+
+// UploadResult record the result of a uploaded file
+type UploadResult struct {
+	File  string `json:"file"`
+	Key   string `json:"key"`
+	ETag  string `json:"etag,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// UploadDirOptions control a recursive directory upload
+type UploadDirOptions struct {
+	ObjectOptions
+	Filter       PathFilter
+	MPUThreshold int64 // files larger than MPUThreshold are uploaded with MPU, others with a single PUT
+	PartSize     int64 // MPU part size, default s3manager.MinUploadPartSize
+	Concurrency  int
+	// concurrent parts of a MPU uploaded file, default 1
+	PartConcurrency int
+}
+
+// UploadDir upload all files under dir(recursively) to bucket,
+// Object key is prefix(a '/' is appended if missing) + file path relative to dir,
+// files match patterns in dir/.s3ignore or opt.Filter are skipped
+func (sc *S3Cli) UploadDir(ctx context.Context, bucket, prefix, dir, contentType string, metadata map[string]*string, opt UploadDirOptions) ([]UploadResult, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	ignore, err := loadIgnoreFile(filepath.Join(dir, s3ignoreFile))
+	if err != nil {
+		return nil, err
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	filter := opt.Filter
+	filter.Exclude = append(append([]string{s3ignoreFile}, filter.Exclude...), ignore...)
+	if err := filter.validate(); err != nil {
+		return nil, err
+	}
+	files, err := walkLocal(dir, filter)
+	if err != nil {
+		return nil, err
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	concurrency := opt.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]UploadResult, len(rels))
+	index := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range index {
+				f := files[rels[i]]
+				r := &results[i]
+				r.File = f.path
+				r.Key = prefix + rels[i]
+				ct := contentType
+				if ct == "" {
+					ct = mime.TypeByExtension(filepath.Ext(f.path))
+				}
+				etag, err := sc.uploadFile(ctx, bucket, r.Key, ct, metadata, f, opt)
+				if err != nil {
+					r.Error = err.Error()
+				}
+				r.ETag = etag
+			}
+		}()
+	}
+	for i := range rels {
+		if ctx.Err() != nil {
+			results[i].File, results[i].Key, results[i].Error = files[rels[i]].path, prefix+rels[i], ctx.Err().Error()
+			continue
+		}
+		index <- i
+	}
+	close(index)
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d files upload failed", failed, len(results))
+	}
+	return results, nil
+}
+
+// customHeaderOption is a request.Option add the custom headers and query of sc
+func (sc *S3Cli) customHeaderOption(r *request.Request) {
+	sc.addCustomHeader(r.HTTPRequest)
+}
+
+// uploadFile upload a local file with the custom headers and query,
+// MPU(of opt.PartSize parts) is used if file is larger than opt.MPUThreshold
+func (sc *S3Cli) uploadFile(ctx context.Context, bucket, key, contentType string, metadata map[string]*string, f localFile, opt UploadDirOptions) (string, error) {
+	fd, err := os.Open(f.path)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+	var objContentType *string
+	if contentType != "" {
+		objContentType = aws.String(contentType)
+	}
+
+	if opt.MPUThreshold > 0 && f.size > opt.MPUThreshold {
+		return sc.uploadFileParts(ctx, fd, f.size, opt.PartSize, opt.PartConcurrency, &s3.CreateMultipartUploadInput{
+			Bucket:      aws.String(bucket),
+			Key:         aws.String(key),
+			ContentType: objContentType,
+			Metadata:    metadata,
+			Tagging:     opt.tagging(),
+
+			ObjectLockMode:            opt.lockMode(),
+			ObjectLockRetainUntilDate: opt.lockRetainUntil(),
+			ObjectLockLegalHoldStatus: opt.legalHold(),
+			WebsiteRedirectLocation:   opt.websiteRedirect(),
+		})
+	}
+
+	out, err := sc.Client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		ContentType: objContentType,
+		Metadata:    metadata,
+		Tagging:     opt.tagging(),
+		Body:        fd,
+
+		ObjectLockMode:            opt.lockMode(),
+		ObjectLockRetainUntilDate: opt.lockRetainUntil(),
+		ObjectLockLegalHoldStatus: opt.legalHold(),
+		WebsiteRedirectLocation:   opt.websiteRedirect(),
+	}, sc.customHeaderOption)
+	if err != nil {
+		return "", err
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+// uploadFileParts upload size bytes of fd with a MPU of partSize(default s3manager.MinUploadPartSize) parts
+// and concurrency parts at a time, a file smaller than partSize is uploaded as a single part,
+// the MPU is aborted if any part failed
+func (sc *S3Cli) uploadFileParts(ctx context.Context, fd io.ReaderAt, size, partSize int64, concurrency int, in *s3.CreateMultipartUploadInput) (string, error) {
+	if partSize < s3manager.MinUploadPartSize {
+		partSize = s3manager.MinUploadPartSize
+	}
+	if size/partSize >= s3manager.MaxUploadParts {
+		partSize = size/s3manager.MaxUploadParts + 1
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	mpu, err := sc.Client.CreateMultipartUploadWithContext(ctx, in, sc.customHeaderOption)
+	if err != nil {
+		return "", err
+	}
+	abort := func() {
+		sc.Client.AbortMultipartUploadWithContext(ctx, &s3.AbortMultipartUploadInput{Bucket: in.Bucket, Key: in.Key, UploadId: mpu.UploadId}, sc.customHeaderOption)
+	}
+
+	partNum := (size + partSize - 1) / partSize
+	if partNum == 0 {
+		partNum = 1
+	}
+	parts := make([]*s3.CompletedPart, partNum)
+	index := make(chan int64)
+	errs := make(chan error, partNum)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range index {
+				off := i * partSize
+				length := partSize
+				if off+length > size {
+					length = size - off
+				}
+				out, err := sc.Client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+					Body:       io.NewSectionReader(fd, off, length),
+					Bucket:     in.Bucket,
+					Key:        in.Key,
+					PartNumber: aws.Int64(i + 1),
+					UploadId:   mpu.UploadId,
+				}, sc.customHeaderOption)
+				if err != nil {
+					errs <- fmt.Errorf("upload part %d failed: %w", i+1, err)
+					continue
+				}
+				parts[i] = &s3.CompletedPart{ETag: out.ETag, PartNumber: aws.Int64(i + 1)}
+			}
+		}()
+	}
+	for i := int64(0); i < partNum && ctx.Err() == nil && len(errs) == 0; i++ {
+		index <- i
+	}
+	close(index)
+	wg.Wait()
+	close(errs)
+
+	err = <-errs
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		abort()
+		return "", err
+	}
+	out, err := sc.Client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          in.Bucket,
+		Key:             in.Key,
+		UploadId:        mpu.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	}, sc.customHeaderOption)
+	if err != nil {
+		abort()
+		return "", err
+	}
+	return aws.StringValue(out.ETag), nil
+}
+
+// HeadOptions select the only field RenderHeadObject print instead of the record
+type HeadOptions struct {
+	Mtime             bool // print LastModified
+	Mtimestamp        bool // print LastModified as unix timestamp
+	ReplicationStatus bool // print x-amz-replication-status
+}
+
+// HeadObject head a Object
+func (sc *S3Cli) HeadObject(ctx context.Context, bucket, key string) (*s3.HeadObjectOutput, error) {
+	req, resp := sc.Client.HeadObjectRequest(&s3.HeadObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetObjectLockConfig
+func (sc *S3Cli) GetObjectLockConfig(ctx context.Context, bucket string) (*s3.GetObjectLockConfigurationOutput, error) {
+	req, resp := sc.Client.GetObjectLockConfigurationRequest(&s3.GetObjectLockConfigurationInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// PutObjectLockConfig put a Bucket's Object Lock configuration,
+// retention(NewDefaultRetention) is the default retention of new Objects, nil for none
+func (sc *S3Cli) PutObjectLockConfig(ctx context.Context, bucket, enabled string, retention *s3.DefaultRetention) error {
+	cfg := &s3.ObjectLockConfiguration{
+		ObjectLockEnabled: aws.String(enabled),
+	}
+	if retention != nil {
+		cfg.Rule = &s3.ObjectLockRule{DefaultRetention: retention}
+	}
+	req, resp := sc.Client.PutObjectLockConfigurationRequest(&s3.PutObjectLockConfigurationInput{
+		Bucket:                  aws.String(bucket),
+		ObjectLockConfiguration: cfg,
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+
+	if resp == nil {
+		return nil
+	}
+
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		return sc.printJSON(cfg)
+	}
+	return nil
+}
+
+// GetObjectACL get A Object's ACL
+func (sc *S3Cli) GetObjectACL(ctx context.Context, bucket, key string) (*s3.GetObjectAclOutput, error) {
+	req, resp := sc.Client.GetObjectAclRequest(&s3.GetObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// SetObjectACL set A Object's ACL
+func (sc *S3Cli) SetObjectACL(ctx context.Context, bucket, key string, acl string) error {
+	req, resp := sc.Client.PutObjectAclRequest(&s3.PutObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		ACL:    aws.String(acl),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// filterObjects return Objects modified in [startTime, endTime]
+func filterObjects(objects []*s3.Object, startTime, endTime time.Time) []*s3.Object {
+	filtered := objects[:0]
+	for _, obj := range objects {
+		if obj.LastModified.Before(startTime) || obj.LastModified.After(endTime) {
+			continue
+		}
+		filtered = append(filtered, obj)
+	}
+	return filtered
+}
+
+// ListAllObjects list all Objects(modified in [startTime, endTime]) in specified bucket,
+// pages are merged into one output, the pages listed before a failed one are returned with the error
+func (sc *S3Cli) ListAllObjects(ctx context.Context, bucket, prefix, delimiter string, startTime, endTime time.Time) (*s3.ListObjectsOutput, error) {
+	var all *s3.ListObjectsOutput
+	err := sc.Client.ListObjectsPagesWithContext(ctx, &s3.ListObjectsInput{
+		Bucket:    aws.String(bucket),
+		Prefix:    aws.String(prefix),
+		Delimiter: aws.String(delimiter),
+	}, func(p *s3.ListObjectsOutput, last bool) (shouldContinue bool) {
+		p.Contents = filterObjects(p.Contents, startTime, endTime)
+		if all == nil {
+			all = p
+		} else {
+			all.Contents = append(all.Contents, p.Contents...)
+			all.CommonPrefixes = append(all.CommonPrefixes, p.CommonPrefixes...)
+			all.IsTruncated, all.NextMarker = p.IsTruncated, p.NextMarker
+		}
+		return true
+	})
+	if err != nil {
+		return all, fmt.Errorf("list all objects failed: %w", err)
+	}
+	return all, nil
+}
+
+// ListAllObjectsV2 list all Objects(modified in [startTime, endTime]) in specified bucket,
+// pages are merged into one output, the pages listed before a failed one are returned with the error
+func (sc *S3Cli) ListAllObjectsV2(ctx context.Context, bucket, prefix, delimiter string, owner bool, startTime, endTime time.Time) (*s3.ListObjectsV2Output, error) {
+	listInput := &s3.ListObjectsV2Input{
+		Bucket:     aws.String(bucket),
+		FetchOwner: aws.Bool(owner),
+	}
+	if prefix != "" {
+		listInput.SetPrefix(prefix)
+	}
+	if delimiter != "" {
+		listInput.SetDelimiter(delimiter)
+	}
+	var all *s3.ListObjectsV2Output
+	err := sc.Client.ListObjectsV2PagesWithContext(ctx, listInput, func(p *s3.ListObjectsV2Output, last bool) (shouldContinue bool) {
+		p.Contents = filterObjects(p.Contents, startTime, endTime)
+		if all == nil {
+			all = p
+		} else {
+			all.Contents = append(all.Contents, p.Contents...)
+			all.CommonPrefixes = append(all.CommonPrefixes, p.CommonPrefixes...)
+			all.IsTruncated, all.NextContinuationToken = p.IsTruncated, p.NextContinuationToken
+			all.KeyCount = aws.Int64(aws.Int64Value(all.KeyCount) + aws.Int64Value(p.KeyCount))
+		}
+		return true
+	})
+	if err != nil {
+		return all, fmt.Errorf("list all objects failed: %w", err)
+	}
+	return all, nil
+}
+
+// ListObjects (S3 listBucket)list Objects(modified in [startTime, endTime]) in specified bucket
+func (sc *S3Cli) ListObjects(ctx context.Context, bucket, prefix, delimiter, marker string, maxkeys int64, startTime, endTime time.Time) (*s3.ListObjectsOutput, error) {
+	listInput := &s3.ListObjectsInput{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		listInput.SetPrefix(prefix)
+	}
+	if delimiter != "" {
+		listInput.SetDelimiter(delimiter)
+	}
+	if marker != "" {
+		listInput.SetMarker(marker)
+	}
+	if maxkeys > 0 {
+		listInput.SetMaxKeys(maxkeys)
+	}
+	req, resp := sc.Client.ListObjectsRequest(listInput)
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("list objects failed: %w", err)
+	}
+	resp.Contents = filterObjects(resp.Contents, startTime, endTime)
+	return resp, nil
+}
+
+// ListObjectsV2 (S3 listBucket)list Objects(modified in [startTime, endTime]) in specified bucket
+func (sc *S3Cli) ListObjectsV2(ctx context.Context, bucket, prefix, delimiter, marker string, maxkeys int64, owner bool, startTime, endTime time.Time) (*s3.ListObjectsV2Output, error) {
+	req, resp := sc.Client.ListObjectsV2Request(&s3.ListObjectsV2Input{
+		Bucket:     aws.String(bucket),
+		Prefix:     aws.String(prefix),
+		StartAfter: aws.String(marker),
+		Delimiter:  aws.String(delimiter),
+		MaxKeys:    aws.Int64(maxkeys),
+		FetchOwner: aws.Bool(owner),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("list objects failed: %w", err)
+	}
+	resp.Contents = filterObjects(resp.Contents, startTime, endTime)
+	return resp, nil
+}
+
+// ListObjectVersions list Objects versions in Bucket
+func (sc *S3Cli) ListObjectVersions(ctx context.Context, bucket, prefix string) (*s3.ListObjectVersionsOutput, error) {
+	lovi := &s3.ListObjectVersionsInput{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		lovi.Prefix = aws.String(prefix)
+	}
+	req, resp := sc.Client.ListObjectVersionsRequest(lovi)
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetObject download a Object from bucket to current directory,
+// an existing local file is only replaced if overwrite is set
+func (sc *S3Cli) GetObject(ctx context.Context, bucket, key, oRange, version string, overwrite bool) (*GetResult, error) {
+	var objRange *string
+	if oRange != "" {
+		objRange = aws.String(fmt.Sprintf("bytes=%s", oRange))
+	}
+	var versionID *string
+	if version != "" {
+		versionID = aws.String(version)
+	}
+	req, resp := sc.Client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: versionID,
+		Range:     objRange,
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	filename := filepath.Base(key)
+	if _, err := os.Stat(filename); err == nil && !overwrite {
+		return nil, fmt.Errorf("local file %s already exists", filename)
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get object %s failed: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	// Create a file to write the S3 Object contents
+	fd, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	n, err := io.Copy(fd, resp.Body)
+	if err != nil {
+		// not leave a truncated file
+		fd.Close()
+		os.Remove(filename)
+		return nil, fmt.Errorf("get object %s failed: %w", key, err)
+	}
+	if oRange == "" && resp.LastModified != nil {
+		if err := os.Chtimes(filename, time.Now(), *resp.LastModified); err != nil {
+			return nil, err
+		}
+	}
+	return &GetResult{
+		Key:       key,
+		File:      filename,
+		Size:      n,
+		ETag:      aws.StringValue(resp.ETag),
+		VersionID: aws.StringValue(resp.VersionId),
+	}, nil
+}
+
+// downloadObject download a Object to local file(create parent directories),
+// the file mtime is set to Object LastModified
+func (sc *S3Cli) downloadObject(ctx context.Context, bucket, key, filename string) error {
+	out, err := sc.Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("get object %s failed: %w", key, err)
+	}
+	defer out.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+		return err
+	}
+	fd, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(fd.Name())
+	if _, err = io.Copy(fd, out.Body); err != nil {
+		fd.Close()
+		return err
+	}
+	if err = fd.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(fd.Name(), filename); err != nil {
+		return err
+	}
+	if out.LastModified != nil {
+		return os.Chtimes(filename, time.Now(), *out.LastModified)
+	}
+	return nil
+}
+
+// DownloadResult record the result of a downloaded Object
+type DownloadResult struct {
+	Key     string `json:"key"`
+	File    string `json:"file"`
+	Size    int64  `json:"size"`
+	Skipped bool   `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// DownloadDir download all Objects with prefix to dir(recursively) and recreate
+// the key hierarchy, the local path is the key relative to the last '/' of prefix
+func (sc *S3Cli) DownloadDir(ctx context.Context, bucket, prefix, dir string, overwrite bool, concurrency int) ([]DownloadResult, error) {
+	objects, err := sc.walkRemote(ctx, bucket, prefix, PathFilter{})
+	if err != nil {
+		return nil, err
+	}
+	base := prefix[:strings.LastIndex(prefix, "/")+1]
+	rels := make([]string, 0, len(objects))
+	for rel := range objects {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]DownloadResult, len(rels))
+	index := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range index {
+				r := &results[i]
+				r.Key = prefix + rels[i]
+				r.Size = aws.Int64Value(objects[rels[i]].Size)
+				filename, err := localPath(dir, strings.TrimPrefix(r.Key, base))
+				if err != nil {
+					r.Error = err.Error()
+				} else if _, err := os.Stat(filename); err == nil && !overwrite {
+					r.File = filename
+					r.Skipped = true
+				} else {
+					r.File = filename
+					if err := sc.downloadObject(ctx, bucket, r.Key, filename); err != nil {
+						r.Error = err.Error()
+					}
+				}
+			}
+		}()
+	}
+	for i := range rels {
+		if ctx.Err() != nil {
+			results[i].Key, results[i].Error = prefix+rels[i], ctx.Err().Error()
+			continue
+		}
+		index <- i
+	}
+	close(index)
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d Objects download failed", failed, len(results))
+	}
+	return results, nil
+}
+
+// CatObject write Object contents to w
+func (sc *S3Cli) CatObject(ctx context.Context, w io.Writer, bucket, key, oRange, version string) error {
+	var objRange *string
+	if oRange != "" {
+		objRange = aws.String(fmt.Sprintf("bytes=%s", oRange))
+	}
+	var versionID *string
+	if version != "" {
+		versionID = aws.String(version)
+	}
+	req, resp := sc.Client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: versionID,
+		Range:     objRange,
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("get object failed: %w", err)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// RenameResult record the result of a rename(copy and delete) Object
+type RenameResult struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Error  string `json:"error,omitempty"`
+}
+
+// RenameObject rename(server-side copy and delete) a Object,
+// or all Objects with the source prefix if source ends with '/'(destination prefix then ends with '/' too)
+func (sc *S3Cli) RenameObject(ctx context.Context, source, bucket, key string, concurrency int) ([]RenameResult, error) {
+	srcBucket, srcKey := sc.SplitKeyValue(source, "/")
+	if srcBucket == "" || srcKey == "" {
+		return nil, fmt.Errorf("invalid source <bucket/key>(%s)", source)
+	}
+	if bucket == "" || key == "" {
+		return nil, fmt.Errorf("invalid destination <bucket/key>(%s/%s)", bucket, key)
+	}
+	if srcBucket == bucket && srcKey == key {
+		return nil, fmt.Errorf("source and destination are the same: %s", source)
+	}
+
+	if !strings.HasSuffix(srcKey, "/") {
+		result := RenameResult{
+			Source: srcBucket + "/" + srcKey,
+			Target: bucket + "/" + key,
+		}
+		err := sc.moveObject(ctx, srcBucket, srcKey, bucket, key, concurrency)
+		if err != nil {
+			return nil, fmt.Errorf("rename %s failed: %w", result.Source, err)
+		}
+		return []RenameResult{result}, nil
+	}
+
+	if !strings.HasSuffix(key, "/") {
+		key += "/"
+	}
+	if srcBucket == bucket && strings.HasPrefix(key, srcKey) {
+		return nil, fmt.Errorf("destination %s is inside source prefix %s", key, srcKey)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	keys := make(chan string)
+	results := make(chan RenameResult)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range keys {
+				dstKey := key + strings.TrimPrefix(k, srcKey)
+				r := RenameResult{
+					Source: srcBucket + "/" + k,
+					Target: bucket + "/" + dstKey,
+				}
+				// every Object is copied by a single worker
+				if err := sc.moveObject(ctx, srcBucket, k, bucket, dstKey, 1); err != nil {
+					r.Error = err.Error()
+				}
+				results <- r
+			}
+		}()
+	}
+
+	var listErr error
+	go func() {
+		defer close(keys)
+		listErr = sc.Client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+			Bucket: aws.String(srcBucket),
+			Prefix: aws.String(srcKey),
+		}, func(p *s3.ListObjectsV2Output, last bool) bool {
+			for _, obj := range p.Contents {
+				select {
+				case keys <- aws.StringValue(obj.Key):
+				case <-ctx.Done():
+					return false
+				}
+			}
+			return true
+		})
+	}()
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var failed int
+	all := []RenameResult{}
+	for r := range results {
+		if r.Error != "" {
+			failed++
+		}
+		all = append(all, r)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Source < all[j].Source })
+
+	if listErr != nil {
+		return all, fmt.Errorf("list objects failed: %w", listErr)
+	}
+	if failed > 0 {
+		return all, fmt.Errorf("%d of %d Objects rename failed", failed, len(all))
+	}
+	return all, nil
+}
+
+// moveObject server-side copy a Object with its metadata, content-type, ACL
+// and tags, then delete the source Object
+func (sc *S3Cli) moveObject(ctx context.Context, srcBucket, srcKey, dstBucket, dstKey string, concurrency int) error {
+	head, err := sc.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("head object failed: %w", err)
+	}
+	grants, err := sc.objectGrants(ctx, srcBucket, srcKey)
+	if err != nil {
+		return err
+	}
+
+	if aws.Int64Value(head.ContentLength) > maxCopyObjectSize {
+		cmi := newCopyMultipartInput(head, dstBucket, dstKey, "", nil)
+		cmi.GrantFullControl = grants.fullControl
+		cmi.GrantRead = grants.read
+		cmi.GrantReadACP = grants.readACP
+		cmi.GrantWriteACP = grants.writeACP
+		if cmi.Tagging, err = sc.objectTagging(ctx, srcBucket, srcKey); err != nil {
+			return err
+		}
+		_, err = sc.copyObjectMultipart(ctx, srcBucket, srcKey, aws.Int64Value(head.ContentLength), cmi, DefaultCopyPartSize, concurrency)
+		if err != nil {
+			return err
+		}
+	} else {
+		// metadata, content-type and tags are copied by default(COPY directive)
+		_, err = sc.Client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+			CopySource:        aws.String(copySource(srcBucket, srcKey)),
+			Bucket:            aws.String(dstBucket),
+			Key:               aws.String(dstKey),
+			MetadataDirective: aws.String(s3.MetadataDirectiveCopy),
+			TaggingDirective:  aws.String(s3.TaggingDirectiveCopy),
+			GrantFullControl:  grants.fullControl,
+			GrantRead:         grants.read,
+			GrantReadACP:      grants.readACP,
+			GrantWriteACP:     grants.writeACP,
+		})
+		if err != nil {
+			return fmt.Errorf("copy object failed: %w", err)
+		}
+	}
+
+	_, err = sc.Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return fmt.Errorf("delete object failed: %w", err)
+	}
+	return nil
+}
+
+// grantHeaders represent an ACL in x-amz-grant-* header format
+type grantHeaders struct {
+	fullControl *string
+	read        *string
+	readACP     *string
+	writeACP    *string
+}
+
+// objectGrants get a Object's ACL as x-amz-grant-* headers,
+// the default ACL(only owner FULL_CONTROL) returns empty headers
+func (sc *S3Cli) objectGrants(ctx context.Context, bucket, key string) (grantHeaders, error) {
+	gh := grantHeaders{}
+	out, err := sc.Client.GetObjectAclWithContext(ctx, &s3.GetObjectAclInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return gh, fmt.Errorf("get object acl failed: %w", err)
+	}
+
+	ownerID := ""
+	if out.Owner != nil {
+		ownerID = aws.StringValue(out.Owner.ID)
+	}
+	isDefault := true
+	grants := map[string][]string{}
+	for _, g := range out.Grants {
+		if g.Grantee == nil {
+			continue
+		}
+		var grantee string
+		switch aws.StringValue(g.Grantee.Type) {
+		case s3.TypeCanonicalUser:
+			grantee = fmt.Sprintf("id=\"%s\"", aws.StringValue(g.Grantee.ID))
+		case s3.TypeGroup:
+			grantee = fmt.Sprintf("uri=\"%s\"", aws.StringValue(g.Grantee.URI))
+		case s3.TypeAmazonCustomerByEmail:
+			grantee = fmt.Sprintf("emailAddress=\"%s\"", aws.StringValue(g.Grantee.EmailAddress))
+		default:
+			continue
+		}
+		permission := aws.StringValue(g.Permission)
+		if permission != s3.PermissionFullControl || aws.StringValue(g.Grantee.ID) != ownerID {
+			isDefault = false
+		}
+		grants[permission] = append(grants[permission], grantee)
+	}
+	if isDefault {
+		return gh, nil
+	}
+
+	join := func(permission string) *string {
+		if v, ok := grants[permission]; ok {
+			return aws.String(strings.Join(v, ", "))
+		}
+		return nil
+	}
+	gh.fullControl = join(s3.PermissionFullControl)
+	gh.read = join(s3.PermissionRead)
+	gh.readACP = join(s3.PermissionReadAcp)
+	gh.writeACP = join(s3.PermissionWriteAcp)
+	return gh, nil
+}
+
+// copyObjectMultipart copy a Object(larger than 5GiB) with UploadPartCopy,
+// cmi specify the destination Object and its metadata
+func (sc *S3Cli) copyObjectMultipart(ctx context.Context, srcBucket, srcKey string, size int64, cmi *s3.CreateMultipartUploadInput, partSize int64, concurrency int) (*s3.CompleteMultipartUploadOutput, error) {
+	if partSize > maxCopyPartSize {
+		return nil, fmt.Errorf("part-size %d exceeds %d(5GiB) of UploadPartCopy", partSize, maxCopyPartSize)
+	}
+	if partSize < s3manager.MinUploadPartSize {
+		partSize = s3manager.MinUploadPartSize
+	}
+	if size > partSize*s3manager.MaxUploadParts {
+		partSize = (size + s3manager.MaxUploadParts - 1) / s3manager.MaxUploadParts
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	mpu, err := sc.Client.CreateMultipartUploadWithContext(ctx, cmi)
+	if err != nil {
+		return nil, fmt.Errorf("create multipart upload failed: %w", err)
+	}
+
+	partNum := (size + partSize - 1) / partSize
+	parts := make([]*s3.CompletedPart, partNum)
+	partCh := make(chan int64)
+	errs := make(chan error, partNum)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range partCh {
+				start := n * partSize
+				end := start + partSize - 1
+				if end >= size {
+					end = size - 1
+				}
+				out, err := sc.Client.UploadPartCopyWithContext(ctx, &s3.UploadPartCopyInput{
+					Bucket:          mpu.Bucket,
+					Key:             mpu.Key,
+					UploadId:        mpu.UploadId,
+					PartNumber:      aws.Int64(n + 1),
+					CopySource:      aws.String(copySource(srcBucket, srcKey)),
+					CopySourceRange: aws.String(fmt.Sprintf("bytes=%d-%d", start, end)),
+				})
+				if err != nil {
+					errs <- fmt.Errorf("upload part %d copy failed: %w", n+1, err)
+					continue
+				}
+				parts[n] = &s3.CompletedPart{
+					PartNumber: aws.Int64(n + 1),
+					ETag:       out.CopyPartResult.ETag,
+				}
+			}
+		}()
+	}
+	for n := int64(0); n < partNum && ctx.Err() == nil && len(errs) == 0; n++ {
+		partCh <- n
+	}
+	close(partCh)
+	wg.Wait()
+	close(errs)
+
+	err = <-errs
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		sc.Client.AbortMultipartUpload(&s3.AbortMultipartUploadInput{
+			Bucket:   mpu.Bucket,
+			Key:      mpu.Key,
+			UploadId: mpu.UploadId,
+		})
+		return nil, err
+	}
+
+	out, err := sc.Client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          mpu.Bucket,
+		Key:             mpu.Key,
+		UploadId:        mpu.UploadId,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("complete multipart upload failed: %w", err)
+	}
+	return out, nil
+}
+
+// newCopyMultipartInput create the CreateMultipartUploadInput of a multipart copy,
+// source metadata and content headers are preserved unless contentType or metadata is specified
+func newCopyMultipartInput(head *s3.HeadObjectOutput, dstBucket, dstKey, contentType string, metadata map[string]*string) *s3.CreateMultipartUploadInput {
+	cmi := &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(dstBucket),
+		Key:    aws.String(dstKey),
+	}
+	if contentType != "" || metadata != nil {
+		// same as REPLACE metadata directive
+		cmi.Metadata = metadata
+		if contentType != "" {
+			cmi.ContentType = aws.String(contentType)
+		}
+		return cmi
+	}
+	cmi.Metadata = head.Metadata
+	cmi.ContentType = head.ContentType
+	cmi.CacheControl = head.CacheControl
+	cmi.ContentDisposition = head.ContentDisposition
+	cmi.ContentEncoding = head.ContentEncoding
+	cmi.ContentLanguage = head.ContentLanguage
+	return cmi
+}
+
+// copySource return the escaped x-amz-copy-source of bucket/key
+func copySource(bucket, key string) string {
+	return bucket + "/" + strings.ReplaceAll(url.PathEscape(key), "%2F", "/")
+}
+
+// objectTagging get a Object's tags in x-amz-tagging header format, nil if no tag
+func (sc *S3Cli) objectTagging(ctx context.Context, bucket, key string) (*string, error) {
+	out, err := sc.Client.GetObjectTaggingWithContext(ctx, &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get object tagging failed: %w", err)
+	}
+	if len(out.TagSet) == 0 {
+		return nil, nil
+	}
+	return aws.String(encodeTagSet(out.TagSet)), nil
+}
+
+// encodeTagSet encode tags to x-amz-tagging header format(k1=v1&k2=v2)
+func encodeTagSet(tags []*s3.Tag) string {
+	v := url.Values{}
+	for _, t := range tags {
+		v.Add(aws.StringValue(t.Key), aws.StringValue(t.Value))
+	}
+	return v.Encode()
+}
+
+// CopyResult is the destination Object of a copy
+type CopyResult struct {
+	Bucket    string `json:"bucket"`
+	Key       string `json:"key"`
+	ETag      string `json:"etag"`
+	VersionID string `json:"versionId,omitempty"`
+}
+
+// CopyObject copy Object to destBucket/key, tags are copied or replaced by opt,
+// Object larger than 5GiB is copied with UploadPartCopy(partSize, concurrency)
+func (sc *S3Cli) CopyObject(ctx context.Context, source, dstBucket, dstKey, contentType string, metadata map[string]*string, partSize int64, concurrency int, opt ObjectOptions) (*CopyResult, error) {
+	directive, err := opt.taggingDirective()
+	if err != nil {
+		return nil, err
+	}
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	if !sc.Presign {
+		srcBucket, srcKey := sc.SplitKeyValue(source, "/")
+		head, err := sc.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(srcBucket),
+			Key:    aws.String(srcKey),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("head object failed: %w", err)
+		}
+		if aws.Int64Value(head.ContentLength) > maxCopyObjectSize {
+			return sc.copyObjectLarge(ctx, srcBucket, srcKey, head, dstBucket, dstKey, contentType, metadata, partSize, concurrency, opt)
+		}
+	}
+
+	ci := &s3.CopyObjectInput{
+		CopySource: aws.String(source),
+		Bucket:     aws.String(dstBucket), // The name of the destination bucket.
+		Key:        aws.String(dstKey),    // The key of the destination object.
+		Metadata:   metadata,
+	}
+	if contentType != "" {
+		ci.ContentType = aws.String(contentType)
+	}
+	if ci.Metadata != nil || ci.ContentType != nil {
+		ci.MetadataDirective = aws.String(s3.MetadataDirectiveReplace)
+	}
+	if directive == s3.TaggingDirectiveReplace {
+		ci.TaggingDirective = aws.String(directive)
+		ci.Tagging = aws.String(opt.Tagging)
+	}
+	ci.ObjectLockMode = opt.lockMode()
+	ci.ObjectLockRetainUntilDate = opt.lockRetainUntil()
+	ci.ObjectLockLegalHoldStatus = opt.legalHold()
+	req, resp := sc.Client.CopyObjectRequest(ci)
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err = req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("copy object failed: %w", err)
+	}
+	result := &CopyResult{Bucket: dstBucket, Key: dstKey, VersionID: aws.StringValue(resp.VersionId)}
+	if resp.CopyObjectResult != nil {
+		result.ETag = aws.StringValue(resp.CopyObjectResult.ETag)
+	}
+	return result, nil
+}
+
+// CopyObjectFrom copy a Object from another S3 service(src),
+// GetObject is streamed to a Multi-Part-Upload without local staging
+func (sc *S3Cli) CopyObjectFrom(ctx context.Context, src *S3Cli, srcBucket, srcKey, dstBucket, dstKey, contentType string, metadata map[string]*string, partSize int64, concurrency int, opt ObjectOptions) (*CopyResult, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	out, err := sc.streamCopy(ctx, src, srcBucket, srcKey, dstBucket, dstKey, contentType, metadata, partSize, concurrency, opt)
+	if err != nil {
+		return nil, err
+	}
+	return &CopyResult{Bucket: dstBucket, Key: dstKey, ETag: aws.StringValue(out.ETag), VersionID: aws.StringValue(out.VersionID)}, nil
+}
+
+// streamCopy stream a Object of src to dstBucket/dstKey with s3manager.Uploader,
+// metadata and content headers are preserved unless contentType or metadata is specified,
+// tags are copied or replaced by opt
+func (sc *S3Cli) streamCopy(ctx context.Context, src *S3Cli, srcBucket, srcKey, dstBucket, dstKey, contentType string, metadata map[string]*string, partSize int64, concurrency int, opt ObjectOptions) (*s3manager.UploadOutput, error) {
+	directive, err := opt.taggingDirective()
+	if err != nil {
+		return nil, err
+	}
+	obj, err := src.Client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(srcBucket),
+		Key:    aws.String(srcKey),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("get source object failed: %w", err)
+	}
+	defer obj.Body.Close()
+
+	size := aws.Int64Value(obj.ContentLength)
+	if partSize < s3manager.MinUploadPartSize {
+		partSize = s3manager.MinUploadPartSize
+	}
+	if size > partSize*s3manager.MaxUploadParts {
+		partSize = (size + s3manager.MaxUploadParts - 1) / s3manager.MaxUploadParts
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	uploader := s3manager.NewUploaderWithClient(sc.Client, func(u *s3manager.Uploader) {
+		u.PartSize = partSize
+		u.Concurrency = concurrency
+	})
+	ui := &s3manager.UploadInput{
+		Bucket: aws.String(dstBucket),
+		Key:    aws.String(dstKey),
+		Body:   obj.Body,
+	}
+	if contentType != "" || metadata != nil {
+		ui.Metadata = metadata
+		if contentType != "" {
+			ui.ContentType = aws.String(contentType)
+		}
+	} else {
+		ui.Metadata = obj.Metadata
+		ui.ContentType = obj.ContentType
+		ui.CacheControl = obj.CacheControl
+		ui.ContentDisposition = obj.ContentDisposition
+		ui.ContentEncoding = obj.ContentEncoding
+		ui.ContentLanguage = obj.ContentLanguage
+	}
+	if directive == s3.TaggingDirectiveReplace {
+		ui.Tagging = opt.tagging()
+	} else if aws.Int64Value(obj.TagCount) > 0 {
+		tags, err := src.objectTagging(ctx, srcBucket, srcKey)
+		if err != nil {
+			return nil, err
+		}
+		ui.Tagging = tags
+	}
+	ui.ObjectLockMode = opt.lockMode()
+	ui.ObjectLockRetainUntilDate = opt.lockRetainUntil()
+	ui.ObjectLockLegalHoldStatus = opt.legalHold()
+	out, err := uploader.UploadWithContext(ctx, ui)
+	if err != nil {
+		return nil, fmt.Errorf("upload object failed: %w", err)
+	}
+	return out, nil
+}
+
+// copyObjectLarge copy a Object larger than 5GiB with CreateMultipartUpload, UploadPartCopy and CompleteMultipartUpload
+func (sc *S3Cli) copyObjectLarge(ctx context.Context, srcBucket, srcKey string, head *s3.HeadObjectOutput, dstBucket, dstKey, contentType string, metadata map[string]*string, partSize int64, concurrency int, opt ObjectOptions) (*CopyResult, error) {
+	cmi := newCopyMultipartInput(head, dstBucket, dstKey, contentType, metadata)
+	directive, err := opt.taggingDirective()
+	if err != nil {
+		return nil, err
+	}
+	if directive == s3.TaggingDirectiveReplace {
+		cmi.Tagging = opt.tagging()
+	} else if cmi.Tagging, err = sc.objectTagging(ctx, srcBucket, srcKey); err != nil {
+		return nil, err
+	}
+	cmi.ObjectLockMode = opt.lockMode()
+	cmi.ObjectLockRetainUntilDate = opt.lockRetainUntil()
+	cmi.ObjectLockLegalHoldStatus = opt.legalHold()
+	if partSize < 1 {
+		partSize = DefaultCopyPartSize
+	}
+	out, err := sc.copyObjectMultipart(ctx, srcBucket, srcKey, aws.Int64Value(head.ContentLength), cmi, partSize, concurrency)
+	if err != nil {
+		return nil, err
+	}
+	return &CopyResult{Bucket: dstBucket, Key: dstKey, ETag: aws.StringValue(out.ETag), VersionID: aws.StringValue(out.VersionId)}, nil
+}
+
+// DeletePrefix delete Objects with prefix, return the deleted Objects(before the failed page)
+func (sc *S3Cli) DeletePrefix(ctx context.Context, bucket, prefix string, bypassGovernance bool) ([]*s3.DeletedObject, error) {
+	deleted := []*s3.DeletedObject{}
+	loi := &s3.ListObjectsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(prefix),
+	}
+	for {
+		req, resp := sc.Client.ListObjectsRequest(loi)
+		req.SetContext(ctx)
+		err := req.Send()
+		if err != nil {
+			return deleted, fmt.Errorf("list object failed: %w", err)
+		}
+		objectNum := len(resp.Contents)
+		if objectNum == 0 {
+			break
+		}
+		objects := make([]*s3.ObjectIdentifier, 0, 1000)
+		for _, obj := range resp.Contents {
+			objects = append(objects, &s3.ObjectIdentifier{Key: obj.Key})
+		}
+		doi := &s3.DeleteObjectsInput{
+			Bucket: aws.String(bucket),
+			Delete: &s3.Delete{
+				Quiet:   aws.Bool(true),
+				Objects: objects,
+			},
+		}
+		if bypassGovernance {
+			doi.BypassGovernanceRetention = aws.Bool(true)
+		}
+		deleteReq, deleteResp := sc.Client.DeleteObjectsRequest(doi)
+		deleteReq.SetContext(ctx)
+		if err := deleteReq.Send(); err != nil {
+			return deleted, fmt.Errorf("delete objects failed: %w", err)
+		}
+		if len(deleteResp.Errors) > 0 {
+			e := deleteResp.Errors[0]
+			return deleted, fmt.Errorf("delete %d objects failed, %s: %s", len(deleteResp.Errors), aws.StringValue(e.Key), aws.StringValue(e.Message))
+		}
+		for _, obj := range objects {
+			deleted = append(deleted, &s3.DeletedObject{Key: obj.Key})
+		}
+
+		if resp.NextMarker != nil {
+			loi.Marker = resp.NextMarker
+		} else if resp.IsTruncated != nil && *resp.IsTruncated {
+			loi.Marker = resp.Contents[objectNum-1].Key
+		} else {
+			break
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteObjects delete Objects, return the deleted Objects and an error if any Object failed
+func (sc *S3Cli) DeleteObjects(ctx context.Context, bucket string, keys []string, bypassGovernance bool) ([]*s3.DeletedObject, error) {
+	objects := make([]*s3.ObjectIdentifier, 0, len(keys))
+	for _, v := range keys {
+		if v == "" {
+			continue
+		}
+		objects = append(objects, &s3.ObjectIdentifier{Key: aws.String(v)})
+	}
+	doi := &s3.DeleteObjectsInput{
+		Bucket: aws.String(bucket),
+		Delete: &s3.Delete{
+			Quiet:   aws.Bool(true),
+			Objects: objects,
+		},
+	}
+	if bypassGovernance {
+		doi.BypassGovernanceRetention = aws.Bool(true)
+	}
+	req, out := sc.Client.DeleteObjectsRequest(doi)
+	req.SetContext(ctx)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	failed := map[string]bool{}
+	for _, e := range out.Errors {
+		failed[aws.StringValue(e.Key)] = true
+	}
+	deleted := make([]*s3.DeletedObject, 0, len(objects))
+	for _, obj := range objects {
+		if !failed[aws.StringValue(obj.Key)] {
+			deleted = append(deleted, &s3.DeletedObject{Key: obj.Key})
+		}
+	}
+	if len(out.Errors) > 0 {
+		e := out.Errors[0]
+		return deleted, fmt.Errorf("delete %d objects failed, %s: %s", len(out.Errors), aws.StringValue(e.Key), aws.StringValue(e.Message))
+	}
+	return deleted, nil
+}
+
+// DeleteBucketAndObjects delete a Bucket, and all its Objects if force
+func (sc *S3Cli) DeleteBucketAndObjects(ctx context.Context, bucket string, force, bypassGovernance bool) ([]*s3.DeletedObject, error) {
+	deleted := []*s3.DeletedObject{}
+	if force {
+		var err error
+		if deleted, err = sc.DeletePrefix(ctx, bucket, "", bypassGovernance); err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, sc.bucketDelete(ctx, bucket)
+}
+
+// DeleteObjectVersion delete a Object(version), or all versions with prefix key if versionID is empty,
+// bypassGovernance delete versions under GOVERNANCE retention
+func (sc *S3Cli) DeleteObjectVersion(ctx context.Context, bucket, key, versionID string, bypassGovernance bool) ([]*s3.DeletedObject, error) {
+	var bypass *bool
+	if bypassGovernance {
+		bypass = aws.Bool(true)
+	}
+	deleted := []*s3.DeletedObject{}
+	if versionID != "" {
+		req, resp := sc.Client.DeleteObjectRequest(&s3.DeleteObjectInput{
+			Bucket:                    aws.String(bucket),
+			Key:                       aws.String(key),
+			VersionId:                 aws.String(versionID),
+			BypassGovernanceRetention: bypass,
+		})
+		req.SetContext(ctx)
+
+		if sc.Presign {
+			s, err := req.Presign(sc.PresignExp)
+			if err == nil {
+				fmt.Fprintln(sc.out(), s)
+			}
+			return nil, err
+		}
+
+		err := req.Send()
+		if err != nil {
+			return nil, err
+		}
+		deleted = append(deleted, &s3.DeletedObject{Key: aws.String(key), VersionId: aws.String(versionID), DeleteMarker: resp.DeleteMarker})
+	} else {
+		req, resp := sc.Client.ListObjectVersionsRequest(&s3.ListObjectVersionsInput{
+			Bucket: aws.String(bucket),
+			Prefix: aws.String(key),
+		})
+		req.SetContext(ctx)
+
+		err := req.Send()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range resp.DeleteMarkers {
+			req, _ := sc.Client.DeleteObjectRequest(&s3.DeleteObjectInput{
+				Bucket:                    aws.String(bucket),
+				Key:                       v.Key,
+				VersionId:                 v.VersionId,
+				BypassGovernanceRetention: bypass,
+			})
+			err := req.Send()
+			if err != nil {
+				return deleted, err
+			}
+			deleted = append(deleted, &s3.DeletedObject{Key: v.Key, VersionId: v.VersionId, DeleteMarker: aws.Bool(true)})
+		}
+
+		for _, v := range resp.Versions {
+			req, _ := sc.Client.DeleteObjectRequest(&s3.DeleteObjectInput{
+				Bucket:                    aws.String(bucket),
+				Key:                       v.Key,
+				VersionId:                 v.VersionId,
+				BypassGovernanceRetention: bypass,
+			})
+			err := req.Send()
+			if err != nil {
+				return deleted, err
+			}
+			deleted = append(deleted, &s3.DeletedObject{Key: v.Key, VersionId: v.VersionId})
+		}
+	}
+	return deleted, nil
+}
+
+// DeleteObject delete a Object
+func (sc *S3Cli) DeleteObject(ctx context.Context, bucket, key string, bypassGovernance bool) ([]*s3.DeletedObject, error) {
+	doi := &s3.DeleteObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if bypassGovernance {
+		doi.BypassGovernanceRetention = aws.Bool(true)
+	}
+	req, resp := sc.Client.DeleteObjectRequest(doi)
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return []*s3.DeletedObject{{Key: aws.String(key), VersionId: resp.VersionId, DeleteMarker: resp.DeleteMarker}}, nil
+}
+
+// RestoreObject restore a Object
+func (sc *S3Cli) RestoreObject(ctx context.Context, bucket, key, version string) error {
+	var versionID *string
+	if version != "" {
+		versionID = aws.String(version)
+	}
+	req, resp := sc.Client.RestoreObjectRequest(&s3.RestoreObjectInput{
+		Bucket:    aws.String(bucket),
+		Key:       aws.String(key),
+		VersionId: versionID,
+		RestoreRequest: &s3.RestoreRequest{
+			Days: aws.Int64(1),
+		},
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return err
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// MPUCreate create Multi-Part-Upload
+func (sc *S3Cli) MPUCreate(ctx context.Context, bucket, key string) (*s3.CreateMultipartUploadOutput, error) {
+	req, resp := sc.Client.CreateMultipartUploadRequest(&s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	// the record of MPUCreate is bucket/key and UploadId
+	if resp.Bucket == nil {
+		resp.Bucket = aws.String(bucket)
+	}
+	if resp.Key == nil {
+		resp.Key = aws.String(key)
+	}
+	return resp, nil
+}
+
+// MPUUpload upload local files as parts(part number to file) of a Multi-Part-Upload,
+// results are sorted by part number, the error reports the failed parts
+func (sc *S3Cli) MPUUpload(ctx context.Context, bucket, key, uid string, file map[int64]string) ([]PartUploadResult, error) {
+	wg := sync.WaitGroup{}
+	mu := sync.Mutex{}
+	results := make([]PartUploadResult, 0, len(file))
+	for i, localfile := range file {
+		wg.Add(1)
+		go func(num int64, filename string) {
+			defer wg.Done()
+			r := PartUploadResult{PartNumber: num}
+			defer func() {
+				mu.Lock()
+				results = append(results, r)
+				mu.Unlock()
+			}()
+			fd, err := os.Open(filename)
+			if err != nil {
+				r.Error = err.Error()
+				return
+			}
+			defer fd.Close()
+			if info, err := fd.Stat(); err == nil {
+				r.Size = info.Size()
+			}
+			req, resp := sc.Client.UploadPartRequest(&s3.UploadPartInput{
+				Body:       fd,
+				Bucket:     aws.String(bucket),
+				Key:        aws.String(key),
+				PartNumber: aws.Int64(num),
+				UploadId:   aws.String(uid),
+			})
+			req.SetContext(ctx)
+
+			err = req.Send()
+			if err != nil {
+				r.Error = err.Error()
+				return
+			}
+			r.ETag = aws.StringValue(resp.ETag)
+		}(i, localfile)
+	}
+	wg.Wait()
+	sort.Slice(results, func(i, j int) bool { return results[i].PartNumber < results[j].PartNumber })
+	return results, partUploadError(results)
+}
+
+// PartUploadResult record the result of a part upload of MPUUpload and MPUUploadFile
+type PartUploadResult struct {
+	PartNumber int64  `json:"partNumber"`
+	Offset     int64  `json:"offset"`
+	Size       int64  `json:"size"`
+	ETag       string `json:"etag,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ParseSize parse a size like 8M, 512K, 1G(binary units), a plain number is in MB
+func ParseSize(v string) (int64, error) {
+	s := strings.ToUpper(strings.TrimSpace(v))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I")
+	shift := uint(20)
+	if n := len(s); n > 0 {
+		switch s[n-1] {
+		case 'K':
+			shift = 10
+			s = s[:n-1]
+		case 'M':
+			shift = 20
+			s = s[:n-1]
+		case 'G':
+			shift = 30
+			s = s[:n-1]
+		}
+	}
+	size, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || size < 1 {
+		return 0, fmt.Errorf("invalid size %s", v)
+	}
+	return size << shift, nil
+}
+
+// ParsePartNumbers parse part numbers like 3,5-9
+func ParsePartNumbers(v string) ([]int64, error) {
+	nums := []int64{}
+	seen := map[int64]bool{}
+	for _, item := range strings.Split(v, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		from, to := item, item
+		if i := strings.Index(item, "-"); i > 0 {
+			from, to = item[:i], item[i+1:]
+		}
+		start, err := strconv.ParseInt(from, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid part-num %s", item)
+		}
+		end, err := strconv.ParseInt(to, 10, 64)
+		if err != nil || start < 1 || end < start || end > s3manager.MaxUploadParts {
+			return nil, fmt.Errorf("invalid part-num %s", item)
+		}
+		for n := start; n <= end; n++ {
+			if !seen[n] {
+				seen[n] = true
+				nums = append(nums, n)
+			}
+		}
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] < nums[j] })
+	return nums, nil
+}
+
+// MPUUploadFile upload byte ranges(part-size) of a local file as parts of a Multi-Part-Upload,
+// all parts are uploaded if partNums is empty, the error reports the failed parts
+func (sc *S3Cli) MPUUploadFile(ctx context.Context, bucket, key, uid, filename string, partSize int64, partNums []int64, concurrency int) ([]PartUploadResult, error) {
+	if partSize < 1 {
+		return nil, fmt.Errorf("invalid part-size %d", partSize)
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	fd, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer fd.Close()
+	info, err := fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	total := (size + partSize - 1) / partSize
+	if total == 0 {
+		total = 1
+	}
+	if total > s3manager.MaxUploadParts {
+		return nil, fmt.Errorf("part-size %d too small, %d parts exceed %d", partSize, total, s3manager.MaxUploadParts)
+	}
+	if len(partNums) == 0 {
+		for n := int64(1); n <= total; n++ {
+			partNums = append(partNums, n)
+		}
+	}
+	for _, n := range partNums {
+		if n > total {
+			return nil, fmt.Errorf("part-num %d out of range, %s has %d parts", n, filename, total)
+		}
+	}
+
+	results := make([]PartUploadResult, len(partNums))
+	index := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range index {
+				n := partNums[i]
+				off := (n - 1) * partSize
+				length := partSize
+				if off+length > size {
+					length = size - off
+				}
+				results[i] = PartUploadResult{PartNumber: n, Offset: off, Size: length}
+				out, err := sc.Client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+					Body:       io.NewSectionReader(fd, off, length),
+					Bucket:     aws.String(bucket),
+					Key:        aws.String(key),
+					PartNumber: aws.Int64(n),
+					UploadId:   aws.String(uid),
+				})
+				if err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].ETag = aws.StringValue(out.ETag)
+			}
+		}()
+	}
+	for i := range partNums {
+		select {
+		case index <- i:
+		case <-ctx.Done():
+			results[i] = PartUploadResult{PartNumber: partNums[i], Error: ctx.Err().Error()}
+		}
+	}
+	close(index)
+	wg.Wait()
+
+	return results, partUploadError(results)
+}
+
+// partUploadError return the error of failed part uploads, nil if all parts uploaded
+func partUploadError(results []PartUploadResult) error {
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d parts upload failed", failed, len(results))
+	}
+	return nil
+}
+
+// MPUAbort abort Multi-Part-Upload
+func (sc *S3Cli) MPUAbort(ctx context.Context, bucket, key, uid string) (*s3.AbortMultipartUploadOutput, error) {
+	req, resp := sc.Client.AbortMultipartUploadRequest(&s3.AbortMultipartUploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uid),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// MPUList list Multi-Part-Uploads
+func (sc *S3Cli) MPUList(ctx context.Context, bucket, prefix string) (*s3.ListMultipartUploadsOutput, error) {
+	var keyPrefix *string
+	if prefix != "" {
+		keyPrefix = aws.String(prefix)
+	}
+	req, resp := sc.Client.ListMultipartUploadsRequest(&s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+		Prefix: keyPrefix,
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// MPUListParts list uploaded parts of a Multi-Part-Upload
+func (sc *S3Cli) MPUListParts(ctx context.Context, bucket, key, uid string) ([]*s3.Part, error) {
+	parts, err := sc.listAllParts(ctx, bucket, key, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return parts, nil
+}
+
+// CompletedParts convert part manifest to CompletedParts sorted by part number,
+// a manifest item is part-num:etag, or etag with part number of its position(from 1)
+func CompletedParts(manifest []string) ([]*s3.CompletedPart, error) {
+	parts := make([]*s3.CompletedPart, 0, len(manifest))
+	seen := map[int64]bool{}
+	for i, v := range manifest {
+		num := int64(i + 1)
+		etag := v
+		if n := strings.Index(v, ":"); n > 0 {
+			pn, err := strconv.ParseInt(v[:n], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid part-num: %v, error: %w", v[:n], err)
+			}
+			num, etag = pn, v[n+1:]
+		}
+		if num < 1 || num > s3manager.MaxUploadParts {
+			return nil, fmt.Errorf("invalid part-num: %d", num)
+		}
+		if etag == "" {
+			return nil, fmt.Errorf("empty etag of part %d", num)
+		}
+		if seen[num] {
+			return nil, fmt.Errorf("duplicate part-num: %d", num)
+		}
+		seen[num] = true
+		parts = append(parts, &s3.CompletedPart{
+			PartNumber: aws.Int64(num),
+			ETag:       aws.String(etag),
+		})
+	}
+	sort.Slice(parts, func(i, j int) bool {
+		return aws.Int64Value(parts[i].PartNumber) < aws.Int64Value(parts[j].PartNumber)
+	})
+	return parts, nil
+}
+
+// MPUCompleteAuto complete Multi-Part-Upload with all parts listed by server
+func (sc *S3Cli) MPUCompleteAuto(ctx context.Context, bucket, key, uid string) (*MPUResult, error) {
+	uploaded, err := sc.listAllParts(ctx, bucket, key, uid)
+	if err != nil {
+		return nil, err
+	}
+	if len(uploaded) == 0 {
+		return nil, fmt.Errorf("no part uploaded of UploadId %s", uid)
+	}
+	parts := make([]*s3.CompletedPart, len(uploaded))
+	for i, p := range uploaded {
+		parts[i] = &s3.CompletedPart{
+			PartNumber: p.PartNumber,
+			ETag:       p.ETag,
+		}
+	}
+	return sc.MPUComplete(ctx, bucket, key, uid, parts)
+}
+
+// MPUComplete complete Multi-Part-Upload with parts
+func (sc *S3Cli) MPUComplete(ctx context.Context, bucket, key, uid string, parts []*s3.CompletedPart) (*MPUResult, error) {
+	req, resp := sc.Client.CompleteMultipartUploadRequest(&s3.CompleteMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		MultipartUpload: &s3.CompletedMultipartUpload{
+			Parts: parts,
+		},
+		UploadId: aws.String(uid),
+	})
+	req.SetContext(ctx)
+
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	err := req.Send()
+	if err != nil {
+		return nil, err
+	}
+	return &MPUResult{
+		Location:  aws.StringValue(resp.Location),
+		UploadID:  uid,
+		ETag:      aws.StringValue(resp.ETag),
+		VersionID: aws.StringValue(resp.VersionId),
+	}, nil
+}
+
+// MPU upload r with s3manager.Uploader(Multi-Part-Upload of partSize)
+func (sc *S3Cli) MPU(ctx context.Context, bucket, key, contentType string, partSize int64, r io.Reader, metadata map[string]*string, opt ObjectOptions) (*MPUResult, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	uploader := s3manager.NewUploaderWithClient(sc.Client, func(u *s3manager.Uploader) {
+		u.PartSize = partSize
+	})
+
+	mi := &s3manager.UploadInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		Metadata: metadata,
+		Tagging:  opt.tagging(),
+		Body:     r,
+
+		ObjectLockMode:            opt.lockMode(),
+		ObjectLockRetainUntilDate: opt.lockRetainUntil(),
+		ObjectLockLegalHoldStatus: opt.legalHold(),
+		WebsiteRedirectLocation:   opt.websiteRedirect(),
+	}
+	if contentType != "" {
+		mi.ContentType = aws.String(contentType)
+	}
+	out, err := uploader.UploadWithContext(ctx, mi)
+	if err != nil {
+		return nil, err
+	}
+	return &MPUResult{
+		Location:  out.Location,
+		UploadID:  out.UploadID,
+		ETag:      aws.StringValue(out.ETag),
+		VersionID: aws.StringValue(out.VersionID),
+	}, nil
+}
+
+// MPUResult record a completed Multi-Part-Upload,
+// Uploaded and Reused are the part counts of a resumed upload(nil otherwise)
+type MPUResult struct {
+	Location  string `json:"location"`
+	UploadID  string `json:"uploadId"`
+	ETag      string `json:"etag"`
+	VersionID string `json:"versionId,omitempty"`
+	Uploaded  *int64 `json:"uploaded,omitempty"`
+	Reused    *int64 `json:"reused,omitempty"`
+}
+
+// listAllParts list all uploaded parts of a Multi-Part-Upload
+func (sc *S3Cli) listAllParts(ctx context.Context, bucket, key, uid string) ([]*s3.Part, error) {
+	parts := []*s3.Part{}
+	err := sc.Client.ListPartsPagesWithContext(ctx, &s3.ListPartsInput{
+		Bucket:   aws.String(bucket),
+		Key:      aws.String(key),
+		UploadId: aws.String(uid),
+	}, func(p *s3.ListPartsOutput, last bool) bool {
+		parts = append(parts, p.Parts...)
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list parts failed: %w", err)
+	}
+	return parts, nil
+}
+
+// mpuFindUpload find the latest in-progress Multi-Part-Upload of bucket/key
+func (sc *S3Cli) mpuFindUpload(ctx context.Context, bucket, key string) (*s3.MultipartUpload, error) {
+	var upload *s3.MultipartUpload
+	err := sc.Client.ListMultipartUploadsPagesWithContext(ctx, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(bucket),
+		Prefix: aws.String(key),
+	}, func(p *s3.ListMultipartUploadsOutput, last bool) bool {
+		for _, u := range p.Uploads {
+			if aws.StringValue(u.Key) != key {
+				continue
+			}
+			if upload == nil || aws.TimeValue(u.Initiated).After(aws.TimeValue(upload.Initiated)) {
+				upload = u
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list multipart uploads failed: %w", err)
+	}
+	return upload, nil
+}
+
+// MPUResume resume the latest in-progress Multi-Part-Upload of bucket/key(create one if not found),
+// parts already on the server whose ETag matches the local MD5 of the byte range are reused,
+// only the missing parts are uploaded before complete
+func (sc *S3Cli) MPUResume(ctx context.Context, bucket, key, contentType string, partSize int64, fd *os.File, metadata map[string]*string, concurrency int, opt ObjectOptions) (*MPUResult, error) {
+	if err := opt.validate(); err != nil {
+		return nil, err
+	}
+	info, err := fd.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size()
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	upload, err := sc.mpuFindUpload(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	serverParts := map[int64]*s3.Part{}
+	var uid string
+	if upload != nil {
+		uid = aws.StringValue(upload.UploadId)
+		parts, err := sc.listAllParts(ctx, bucket, key, uid)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range parts {
+			serverParts[aws.Int64Value(p.PartNumber)] = p
+		}
+		// the part size of the upload in progress wins
+		if p, ok := serverParts[1]; ok && aws.Int64Value(p.Size) != partSize && aws.Int64Value(p.Size) < size {
+			if sc.verboseOutput() {
+				fmt.Fprintf(os.Stderr, "use part-size %d of UploadId %s\n", aws.Int64Value(p.Size), uid)
+			}
+			partSize = aws.Int64Value(p.Size)
+		}
+	} else {
+		cmi := &s3.CreateMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			Metadata: metadata,
+			Tagging:  opt.tagging(),
+
+			ObjectLockMode:            opt.lockMode(),
+			ObjectLockRetainUntilDate: opt.lockRetainUntil(),
+			ObjectLockLegalHoldStatus: opt.legalHold(),
+			WebsiteRedirectLocation:   opt.websiteRedirect(),
+		}
+		if contentType != "" {
+			cmi.ContentType = aws.String(contentType)
+		}
+		out, err := sc.Client.CreateMultipartUploadWithContext(ctx, cmi)
+		if err != nil {
+			return nil, fmt.Errorf("create multipart upload failed: %w", err)
+		}
+		uid = aws.StringValue(out.UploadId)
+	}
+	if partSize < 1 {
+		return nil, fmt.Errorf("invalid part-size %d", partSize)
+	}
+
+	partNum := (size + partSize - 1) / partSize
+	if partNum == 0 {
+		partNum = 1
+	}
+	if partNum > s3manager.MaxUploadParts {
+		return nil, fmt.Errorf("part-size %d too small, %d parts exceed %d", partSize, partNum, s3manager.MaxUploadParts)
+	}
+	completed := make([]*s3.CompletedPart, partNum)
+	var uploaded, reused int64
+	mu := sync.Mutex{}
+	numCh := make(chan int64)
+	errs := make(chan error, partNum)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numCh {
+				off := (n - 1) * partSize
+				length := partSize
+				if off+length > size {
+					length = size - off
+				}
+				section := io.NewSectionReader(fd, off, length)
+				if p, ok := serverParts[n]; ok && aws.Int64Value(p.Size) == length {
+					h := md5.New()
+					if _, err := io.Copy(h, section); err != nil {
+						errs <- err
+						continue
+					}
+					if strings.Trim(aws.StringValue(p.ETag), "\"") == hex.EncodeToString(h.Sum(nil)) {
+						completed[n-1] = &s3.CompletedPart{PartNumber: aws.Int64(n), ETag: p.ETag}
+						mu.Lock()
+						reused++
+						mu.Unlock()
+						continue
+					}
+					section.Seek(0, io.SeekStart)
+				}
+				out, err := sc.Client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+					Body:       section,
+					Bucket:     aws.String(bucket),
+					Key:        aws.String(key),
+					PartNumber: aws.Int64(n),
+					UploadId:   aws.String(uid),
+				})
+				if err != nil {
+					errs <- fmt.Errorf("upload part %d failed: %w", n, err)
+					continue
+				}
+				completed[n-1] = &s3.CompletedPart{PartNumber: aws.Int64(n), ETag: out.ETag}
+				mu.Lock()
+				uploaded++
+				mu.Unlock()
+			}
+		}()
+	}
+	for n := int64(1); n <= partNum && ctx.Err() == nil && len(errs) == 0; n++ {
+		numCh <- n
+	}
+	close(numCh)
+	wg.Wait()
+	close(errs)
+	err = <-errs
+	if err == nil && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		// keep the upload, so it can be resumed again
+		return nil, fmt.Errorf("UploadId %s: %w", uid, err)
+	}
+
+	out, err := sc.Client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        aws.String(uid),
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: completed},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("complete multipart upload %s failed: %w", uid, err)
+	}
+
+	return &MPUResult{
+		Location:  aws.StringValue(out.Location),
+		UploadID:  uid,
+		ETag:      aws.StringValue(out.ETag),
+		VersionID: aws.StringValue(out.VersionId),
+		Uploaded:  &uploaded,
+		Reused:    &reused,
+	}, nil
+}
@@ -0,0 +1,965 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	mrand "math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+var (
+	testBucketName    = "bucket0001"
+	testObjectKey     = "key0001"
+	testObjectContent = []byte("testObjectContents")
+)
+
+func randomString() string {
+	buf := make([]byte, 10)
+	_, err := rand.Read(buf)
+	if err != nil {
+		for i := 0; i < 16; i++ {
+			buf[i] = byte(mrand.Intn(128))
+		}
+	}
+	return hex.EncodeToString(buf)
+}
+
+func Test_bucketCreate(t *testing.T) {
+	buckets := make([]string, 3)
+	for i := range buckets {
+		bucket := randomString()
+		if exists, err := s3Backend.BucketExists(bucket); err != nil || exists {
+			continue
+		}
+		buckets[i] = bucket
+	}
+
+	_, err := s3cliTest.BucketCreate(context.Background(), buckets)
+	if err != nil {
+		t.Errorf("bucketCreate failed: %s", err)
+	}
+}
+
+func Test_bucketList(t *testing.T) {
+	out, err := s3cliTest.BucketList(context.Background())
+	if err != nil {
+		t.Fatalf("listBuckets failed: %s", err)
+	}
+	found := false
+	for _, b := range out.Buckets {
+		if aws.StringValue(b.Name) == testBucketName {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("listBuckets expect %s in %v", testBucketName, out.Buckets)
+	}
+}
+
+func Test_bucketHead(t *testing.T) {
+	if _, err := s3cliTest.BucketHead(context.Background(), testBucketName); err != nil {
+		t.Error("bucketHead error: ", err)
+	}
+}
+
+func Test_bucketACLGet(t *testing.T) {
+	if _, err := s3cliTest.BucketACLGet(context.Background(), testBucketName); err != nil {
+		t.Error("bucketACLGet error: ", err)
+	}
+}
+
+func Test_bucketACLSet(t *testing.T) {
+	t.Skip("seems gofakes3 set bucketACL has bug")
+	if err := s3cliTest.BucketACLSet(context.Background(), testBucketName, s3.BucketCannedACLPublicReadWrite); err != nil {
+		t.Error("bucketACLSet error: ", err)
+	}
+}
+
+func Test_bucketPolicyGet(t *testing.T) {
+	if _, err := s3cliTest.BucketPolicyGet(context.Background(), testBucketName); err != nil {
+		t.Error("bucketACLGet error: ", err)
+	}
+}
+
+func Test_bucketPolicySet(t *testing.T) {
+	t.Skip("not read to test")
+	if err := s3cliTest.BucketPolicySet(context.Background(), testBucketName, "{}"); err != nil {
+		t.Error("bucketPolicySet error: ", err)
+	}
+}
+
+func Test_bucketVersioningGet(t *testing.T) {
+	if _, err := s3cliTest.BucketVersioningGet(context.Background(), testBucketName); err != nil {
+		t.Error("bucketVersioningGet error: ", err)
+	}
+}
+
+func Test_bucketVersioningSet(t *testing.T) {
+	if err := s3cliTest.BucketVersioningSet(context.Background(), testBucketName, s3.BucketVersioningStatusEnabled); err != nil {
+		t.Errorf("bucketVersioningSet failed: %s", err)
+	}
+}
+
+func Test_bucketDelete(t *testing.T) {
+	bucket := "bucketToDelete"
+	if err := s3Backend.CreateBucket(bucket); err != nil {
+		t.Error("backend CreateBucket error: ", err)
+		return
+	}
+	if err := s3cliTest.bucketDelete(context.Background(), bucket); err != nil {
+		t.Errorf("bucketDelete %s failed: %s", bucket, err)
+	}
+}
+
+func Test_putObject(t *testing.T) {
+	key := "testPutObject"
+	if _, err := s3cliTest.PutObject(context.Background(), testBucketName, key, "", nil, false, bytes.NewReader(nil), ObjectOptions{}); err != nil {
+		t.Errorf("putObject failed: %s", err)
+		return
+	}
+	_, err := s3Backend.GetObject(testBucketName, key, nil)
+	if err != nil {
+		t.Errorf("backend GetObject failed: %s", err)
+		return
+	}
+}
+
+func Test_uploadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		".s3ignore":        "# ignore logs\n*.log\nskipdir/\n",
+		"a.txt":            "aaaa",
+		"big.bin":          "bigbigbigbig",
+		"sub/b.txt":        "bbbb",
+		"sub/c.log":        "cccc",
+		"skipdir/d.txt":    "dddd",
+		"sub/skip/e.tmp":   "eeee",
+		"sub/deep/f/g.txt": "gggg",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	opt := UploadDirOptions{
+		Filter:       PathFilter{Exclude: []string{"*.tmp"}},
+		MPUThreshold: 10,
+		Concurrency:  2,
+	}
+	if _, err := s3cliTest.UploadDir(context.Background(), testBucketName, "uploadDir/", dir, "", nil, opt); err != nil {
+		t.Fatalf("uploadDir failed: %s", err)
+	}
+	for _, k := range []string{"a.txt", "big.bin", "sub/b.txt", "sub/deep/f/g.txt"} {
+		obj, err := s3Backend.GetObject(testBucketName, "uploadDir/"+k, nil)
+		if err != nil {
+			t.Errorf("uploadDir backend GetObject %s failed: %s", k, err)
+			continue
+		}
+		obj.Contents.Close()
+		if obj.Size != int64(len(files[k])) {
+			t.Errorf("uploadDir %s size expect: %d, got: %d", k, len(files[k]), obj.Size)
+		}
+	}
+	for _, k := range []string{".s3ignore", "sub/c.log", "skipdir/d.txt", "sub/skip/e.tmp"} {
+		if _, err := s3Backend.HeadObject(testBucketName, "uploadDir/"+k); err == nil {
+			t.Errorf("uploadDir should skip %s", k)
+		}
+	}
+}
+
+// mpuCounter is a s3iface.S3API that count the created MPUs
+type mpuCounter struct {
+	s3iface.S3API
+	n int32
+}
+
+func (c *mpuCounter) CreateMultipartUploadWithContext(ctx aws.Context, in *s3.CreateMultipartUploadInput, opts ...request.Option) (*s3.CreateMultipartUploadOutput, error) {
+	atomic.AddInt32(&c.n, 1)
+	return c.S3API.CreateMultipartUploadWithContext(ctx, in, opts...)
+}
+
+func Test_uploadDirMPUThreshold(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"small.txt": []byte("small"),
+		"big.bin":   bytes.Repeat([]byte("b"), int(s3manager.MinUploadPartSize)+10),
+		"empty.bin": nil,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	counter := &mpuCounter{S3API: s3cliTest.Client}
+	sc := s3cliTest
+	sc.Client = counter
+	// part size larger than big.bin, MPU is chosen by threshold only
+	opt := UploadDirOptions{MPUThreshold: 5, PartSize: 64 << 20, Concurrency: 2}
+	if _, err := sc.UploadDir(context.Background(), testBucketName, "uploadDirMPU", dir, "", nil, opt); err != nil {
+		t.Fatalf("uploadDir failed: %s", err)
+	}
+	if counter.n != 1 {
+		t.Errorf("uploadDir expect 1 MPU(big.bin), got %d", counter.n)
+	}
+	for name, content := range files {
+		obj, err := s3Backend.GetObject(testBucketName, "uploadDirMPU/"+name, nil)
+		if err != nil {
+			t.Errorf("uploadDir prefix without / backend GetObject %s failed: %s", name, err)
+			continue
+		}
+		obj.Contents.Close()
+		if obj.Size != int64(len(content)) {
+			t.Errorf("uploadDir %s size expect: %d, got: %d", name, len(content), obj.Size)
+		}
+	}
+
+	counter.n = 0
+	opt = UploadDirOptions{MPUThreshold: 1, Concurrency: 1}
+	if _, err := sc.UploadDir(context.Background(), testBucketName, "uploadDirMPU/", dir, "", nil, opt); err != nil {
+		t.Fatalf("uploadDir failed: %s", err)
+	}
+	if counter.n != 2 {
+		t.Errorf("uploadDir expect 2 MPU(small.txt and big.bin), got %d", counter.n)
+	}
+}
+
+func Test_uploadDirCustomHeader(t *testing.T) {
+	dir := t.TempDir()
+	content := make([]byte, 2*s3manager.MinUploadPartSize+10)
+	mrand.Read(content)
+	files := map[string][]byte{"small.txt": []byte("small"), "big.bin": content}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	sc, h := newHeaderRecorderCli(t, "bucket-upload-header")
+	sc.Header = []string{"X-Custom:upload-dir"}
+	opt := UploadDirOptions{MPUThreshold: 1 << 20, Concurrency: 2, PartConcurrency: 3}
+	if _, err := sc.UploadDir(context.Background(), "bucket-upload-header", "", dir, "", nil, opt); err != nil {
+		t.Fatalf("uploadDir failed: %s", err)
+	}
+	for _, method := range []string{http.MethodPut, http.MethodPost} {
+		if got := h.headers[method].Get("X-Custom"); got != "upload-dir" {
+			t.Errorf("uploadDir %s expect custom header, got %q", method, got)
+		}
+	}
+	for name, data := range files {
+		out, err := sc.Client.GetObject(&s3.GetObjectInput{Bucket: aws.String("bucket-upload-header"), Key: aws.String(name)})
+		if err != nil {
+			t.Fatalf("getObject %s failed: %s", name, err)
+		}
+		got, _ := io.ReadAll(out.Body)
+		out.Body.Close()
+		if !bytes.Equal(got, data) {
+			t.Errorf("uploadDir %s content mismatch(%d bytes, expect %d)", name, len(got), len(data))
+		}
+	}
+}
+
+func Test_headObject(t *testing.T) {
+	out, err := s3cliTest.HeadObject(context.Background(), testBucketName, testObjectKey)
+	if err != nil {
+		t.Fatalf("headObject failed: %s", err)
+	}
+	if out.ContentLength == nil {
+		t.Errorf("headObject expect ContentLength, got %v", out)
+	}
+}
+
+func Test_getObjectACL(t *testing.T) {
+	if _, err := s3cliTest.GetObjectACL(context.Background(), testBucketName, testObjectKey); err != nil {
+		t.Errorf("getObjectACL failed: %s", err)
+	}
+}
+
+func Test_setObjectACL(t *testing.T) {
+	if err := s3cliTest.SetObjectACL(context.Background(), testBucketName, testObjectKey, s3.ObjectCannedACLPublicRead); err != nil {
+		t.Errorf("setObjectACL failed: %s", err)
+	}
+}
+
+func Test_listAllObjects(t *testing.T) {
+	out, err := s3cliTest.ListAllObjects(context.Background(), testBucketName, testObjectKey, "/", time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("listAllObjects failed: %s", err)
+	}
+	if len(out.Contents) == 0 || aws.StringValue(out.Contents[0].Key) != testObjectKey {
+		t.Errorf("listAllObjects expect %s, got %v", testObjectKey, out.Contents)
+	}
+
+	// Objects not modified in [startTime, endTime] are filtered
+	out, err = s3cliTest.ListAllObjects(context.Background(), testBucketName, testObjectKey, "/", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("listAllObjects failed: %s", err)
+	}
+	if len(out.Contents) != 0 {
+		t.Errorf("listAllObjects expect no Object modified before zero time, got %v", out.Contents)
+	}
+}
+
+func Test_listObjects(t *testing.T) {
+	out, err := s3cliTest.ListObjects(context.Background(), testBucketName, testObjectKey, "/", "", 1000, time.Time{}, time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("listObjects failed: %s", err)
+	}
+	sc := s3cliTest
+	buf := &bytes.Buffer{}
+	sc.Writer = buf
+	if err := sc.RenderObjects(out, true, nil); err != nil {
+		t.Errorf("renderObjects failed: %s", err)
+	}
+	if !strings.HasPrefix(buf.String(), "0\t") {
+		t.Errorf("renderObjects index expect records start with 0, got %q", buf.String())
+	}
+}
+
+func Test_listObjectVersions(t *testing.T) {
+	if _, err := s3cliTest.ListObjectVersions(context.Background(), testBucketName, ""); err != nil {
+		t.Errorf("listObjectVersions failed: %s", err)
+	}
+}
+
+func Test_getObject(t *testing.T) {
+	key := "keyToTestGetObject"
+	_, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Fatalf("getObject backend PutObject failed: %s", err)
+	}
+	defer os.Remove(key)
+	result, err := s3cliTest.GetObject(context.Background(), testBucketName, key, "", "", true)
+	if err != nil {
+		t.Errorf("getObject failed: %s", err)
+		return
+	}
+	if result.File != key || result.Size != int64(len(testObjectContent)) {
+		t.Errorf("getObject unexpected result %+v", *result)
+	}
+
+	_, err = s3cliTest.GetObject(context.Background(), testBucketName, key, "", "", false)
+	if err == nil {
+		t.Errorf("getObject should not overwrite local file %s", key)
+	}
+}
+
+func Test_downloadDir(t *testing.T) {
+	keys := []string{"downloadDir/a", "downloadDir/sub/b", "downloadDir/sub/deep/c", "downloadDir/../escape"}
+	for _, k := range keys {
+		_, err := s3Backend.PutObject(testBucketName, k, nil, bytes.NewReader([]byte(k)), int64(len(k)))
+		if err != nil {
+			t.Fatalf("downloadDir backend PutObject failed: %s", err)
+		}
+	}
+	dir := t.TempDir()
+	dest := filepath.Join(dir, "dest")
+	_, err := s3cliTest.DownloadDir(context.Background(), testBucketName, "downloadDir/", dest, false, 2)
+	if err == nil {
+		t.Errorf("downloadDir should refuse key escaping destination")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape")); err == nil {
+		t.Errorf("downloadDir key escaped destination")
+	}
+	for _, k := range keys[:3] {
+		name := filepath.Join(dest, filepath.FromSlash(strings.TrimPrefix(k, "downloadDir/")))
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Errorf("downloadDir read %s failed: %s", name, err)
+			continue
+		}
+		if string(data) != k {
+			t.Errorf("downloadDir %s expect: %s, got: %s", name, k, data)
+		}
+	}
+
+	// prefix without trailing '/' is kept as local directory
+	if _, err := s3cliTest.DownloadDir(context.Background(), testBucketName, "downloadDir/sub", dest, false, 2); err != nil {
+		t.Errorf("downloadDir failed: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "sub", "deep", "c")); err != nil {
+		t.Errorf("downloadDir stat failed: %s", err)
+	}
+}
+
+func Test_catObject(t *testing.T) {
+	key := "keyToTestCatObject"
+	_, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Fatalf("catObject backend PutObject failed: %s", err)
+	}
+	buf := &bytes.Buffer{}
+	if err := s3cliTest.CatObject(context.Background(), buf, testBucketName, key, "", ""); err != nil {
+		t.Errorf("catObject failed: %s", err)
+	}
+	if !bytes.Equal(buf.Bytes(), testObjectContent) {
+		t.Errorf("catObject expect %s, got %s", testObjectContent, buf.Bytes())
+	}
+}
+
+func Test_renameObject(t *testing.T) {
+	key := "keyToTestRenameObject"
+	_, err := s3Backend.PutObject(testBucketName, key, map[string]string{"Content-Type": "text/plain"}, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Errorf("renameObject backend PutObject failed: %s", err)
+		return
+	}
+	source := fmt.Sprintf("%s/%s", testBucketName, key)
+	if _, err := s3cliTest.RenameObject(context.Background(), source, testBucketName, key+".renamed", 1); err != nil {
+		t.Errorf("renameObject failed: %s", err)
+		return
+	}
+	if _, err := s3Backend.HeadObject(testBucketName, key); err == nil {
+		t.Errorf("renameObject source %s not deleted", key)
+	}
+	obj, err := s3Backend.HeadObject(testBucketName, key+".renamed")
+	if err != nil {
+		t.Errorf("renameObject backend HeadObject failed: %s", err)
+		return
+	}
+	if obj.Metadata["Content-Type"] != "text/plain" {
+		t.Errorf("renameObject content-type expect: text/plain, got: %s", obj.Metadata["Content-Type"])
+	}
+}
+
+func Test_renameObjectPrefix(t *testing.T) {
+	keys := []string{"renameDir/k1", "renameDir/k2", "renameDir/sub/k3"}
+	for _, k := range keys {
+		_, err := s3Backend.PutObject(testBucketName, k, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+		if err != nil {
+			t.Errorf("renameObjectPrefix backend PutObject failed: %s", err)
+			return
+		}
+	}
+	source := fmt.Sprintf("%s/%s", testBucketName, "renameDir/")
+	results, err := s3cliTest.RenameObject(context.Background(), source, testBucketName, "renamedDir/", 2)
+	if err != nil {
+		t.Errorf("renameObject prefix failed: %s", err)
+		return
+	}
+	if len(results) != len(keys) || results[0].Source != testBucketName+"/renameDir/k1" || results[0].Target != testBucketName+"/renamedDir/k1" {
+		t.Errorf("renameObject prefix expect %d sorted results, got %v", len(keys), results)
+	}
+	for _, k := range keys {
+		if _, err := s3Backend.HeadObject(testBucketName, k); err == nil {
+			t.Errorf("renameObject prefix source %s not deleted", k)
+		}
+		newKey := "renamedDir/" + strings.TrimPrefix(k, "renameDir/")
+		if _, err := s3Backend.HeadObject(testBucketName, newKey); err != nil {
+			t.Errorf("renameObject prefix backend HeadObject %s failed: %s", newKey, err)
+		}
+	}
+
+	// destination prefix without '/' is renamed to a directory too
+	source = fmt.Sprintf("%s/%s", testBucketName, "renamedDir/")
+	if _, err := s3cliTest.RenameObject(context.Background(), source, testBucketName, "renameDir", 2); err != nil {
+		t.Errorf("renameObject prefix without / failed: %s", err)
+		return
+	}
+	for _, k := range keys {
+		if _, err := s3Backend.HeadObject(testBucketName, k); err != nil {
+			t.Errorf("renameObject prefix without / backend HeadObject %s failed: %s", k, err)
+		}
+	}
+
+	source = fmt.Sprintf("%s/%s", testBucketName, "renameDir/")
+	if _, err := s3cliTest.RenameObject(context.Background(), source, testBucketName, "renameDir/sub/", 2); err == nil {
+		t.Errorf("renameObject into source prefix should fail")
+	}
+}
+
+func Test_copyObject(t *testing.T) {
+	source := fmt.Sprintf("%s/%s", testBucketName, testObjectKey)
+	newKey := "testCopyObjectKey"
+	if _, err := s3cliTest.CopyObject(context.Background(), source, testBucketName, newKey, "", nil, 0, 1, ObjectOptions{}); err != nil {
+		t.Errorf("copyObject failed: %s", err)
+		return
+	}
+	if _, err := s3Backend.HeadObject(testBucketName, newKey); err != nil {
+		t.Errorf("copyObject backand HeadObject failed: %s", err)
+	}
+}
+
+func Test_copyObjectMultipart(t *testing.T) {
+	ctx := context.Background()
+	key := "copy-multipart/src"
+	data := make([]byte, s3manager.MinUploadPartSize+100)
+	rand.Read(data)
+	if _, err := s3Backend.PutObject(testBucketName, key, map[string]string{"Content-Type": "text/plain"}, bytes.NewReader(data), int64(len(data))); err != nil {
+		t.Fatal("backend PutObject failed: ", err)
+	}
+	sc := s3cliTest
+	sc.Client = newFaultClient(s3cliTest.Client)
+	if _, err := sc.copyObjectMultipart(ctx, testBucketName, key, int64(len(data)), &s3.CreateMultipartUploadInput{}, maxCopyPartSize+1, 1); err == nil {
+		t.Error("copyObjectMultipart part-size larger than 5GiB expect error")
+	}
+
+	defer func(size int64) { maxCopyObjectSize = size }(maxCopyObjectSize)
+	maxCopyObjectSize = 1024
+	opt := ObjectOptions{Tagging: "copy=multipart"}
+	if _, err := sc.CopyObject(ctx, testBucketName+"/"+key, testBucketName, "copy-multipart/dst", "", nil, s3manager.MinUploadPartSize, 2, opt); err != nil {
+		t.Fatal("copyObject multipart failed: ", err)
+	}
+	obj, err := s3Backend.GetObject(testBucketName, "copy-multipart/dst", nil)
+	if err != nil {
+		t.Fatal("backend GetObject failed: ", err)
+	}
+	defer obj.Contents.Close()
+	got := &bytes.Buffer{}
+	got.ReadFrom(obj.Contents)
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("copyObject multipart content mismatch, size %d, expect %d", got.Len(), len(data))
+	}
+	if obj.Metadata["Content-Type"] != "text/plain" {
+		t.Errorf("copyObject multipart content-type expect: text/plain, got: %s", obj.Metadata["Content-Type"])
+	}
+}
+
+func Test_newCopyMultipartInput(t *testing.T) {
+	head := &s3.HeadObjectOutput{
+		ContentType:  aws.String("text/plain"),
+		CacheControl: aws.String("no-cache"),
+		Metadata:     map[string]*string{"Src": aws.String("v")},
+	}
+	cmi := newCopyMultipartInput(head, "dst", "key", "", nil)
+	if aws.StringValue(cmi.ContentType) != "text/plain" || aws.StringValue(cmi.CacheControl) != "no-cache" || aws.StringValue(cmi.Metadata["Src"]) != "v" {
+		t.Errorf("expect source metadata preserved, got %v", cmi)
+	}
+
+	cmi = newCopyMultipartInput(head, "dst", "key", "image/png", map[string]*string{"New": aws.String("v")})
+	if aws.StringValue(cmi.ContentType) != "image/png" || cmi.CacheControl != nil || cmi.Metadata["Src"] != nil || aws.StringValue(cmi.Metadata["New"]) != "v" {
+		t.Errorf("expect metadata replaced, got %v", cmi)
+	}
+}
+
+func Test_deleteObjects(t *testing.T) {
+	prefix := "testPrefix"
+	if _, err := s3cliTest.DeletePrefix(context.Background(), testBucketName, prefix, false); err != nil {
+		t.Errorf("deleteObjects failed: %s", err)
+	}
+}
+
+func Test_deleteBucketAndObjects(t *testing.T) {
+	bucket := "bucketNameToDelete"
+
+	if err := s3Backend.CreateBucket(bucket); err != nil {
+		t.Errorf("deleteBucketAndObjects backend CreateBucket failed: %s", err)
+		return
+	}
+	_, err := s3Backend.PutObject(testBucketName, "key01", nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Errorf("deleteBucketAndObjects backend PutObject failed: %s", err)
+		return
+	}
+	_, err = s3Backend.PutObject(testBucketName, "key02", nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Errorf("deleteBucketAndObjects backend PutObject failed: %s", err)
+		return
+	}
+
+	if _, err := s3cliTest.DeleteBucketAndObjects(context.Background(), bucket, true, false); err != nil {
+		t.Errorf("deleteBucketAndObjects failed: %s", err)
+	}
+}
+
+func Test_deleteObject(t *testing.T) {
+	key := "keyToTestDeleteObject"
+	_, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Errorf("deleteObject backend PutObject failed: %s", err)
+		return
+	}
+
+	if _, err := s3cliTest.DeleteObject(context.Background(), testBucketName, key, false); err != nil {
+		t.Errorf("deleteObject failed: %s", err)
+	}
+}
+
+func Test_mpuCreate(t *testing.T) {
+	if _, err := s3cliTest.MPUCreate(context.Background(), testBucketName, "key"); err != nil {
+		t.Errorf("mpuCreate failed: %s", err)
+	}
+}
+
+func Test_mpuUpload(t *testing.T) {
+	t.Skip("not ready to test")
+	files := map[int64]string{
+		1: "filename1",
+		2: "filename2",
+	}
+	if _, err := s3cliTest.MPUUpload(context.Background(), testBucketName, "key", "upload-id", files); err != nil {
+		t.Errorf("mpuUpload failed: %s", err)
+	}
+}
+
+func Test_parseSize(t *testing.T) {
+	cases := map[string]int64{
+		"8":     8 << 20,
+		"8M":    8 << 20,
+		"8MB":   8 << 20,
+		"512k":  512 << 10,
+		"1GiB":  1 << 30,
+		" 16m ": 16 << 20,
+	}
+	for k, v := range cases {
+		if got, err := ParseSize(k); err != nil || got != v {
+			t.Errorf("ParseSize(%q) expect %d, got %d, %v", k, v, got, err)
+		}
+	}
+	for _, v := range []string{"", "M", "-1M", "0", "8T"} {
+		if _, err := ParseSize(v); err == nil {
+			t.Errorf("ParseSize(%q) expect error", v)
+		}
+	}
+}
+
+func Test_parsePartNumbers(t *testing.T) {
+	nums, err := ParsePartNumbers("5-9,3,7")
+	if err != nil {
+		t.Fatal("ParsePartNumbers failed: ", err)
+	}
+	if fmt.Sprint(nums) != "[3 5 6 7 8 9]" {
+		t.Errorf("expect [3 5 6 7 8 9], got %v", nums)
+	}
+	for _, v := range []string{"0", "x", "9-5", "1-x", "10001"} {
+		if _, err := ParsePartNumbers(v); err == nil {
+			t.Errorf("ParsePartNumbers(%q) expect error", v)
+		}
+	}
+}
+
+func Test_mpuUploadFile(t *testing.T) {
+	ctx := context.Background()
+	key := "mpu-upload-file"
+	partSize := int64(s3manager.MinUploadPartSize)
+	data := make([]byte, 2*partSize+100)
+	rand.Read(data)
+	filename := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmo, err := s3cliTest.Client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(testBucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		t.Fatal("CreateMultipartUpload failed: ", err)
+	}
+	uid := aws.StringValue(cmo.UploadId)
+
+	if _, err := s3cliTest.MPUUploadFile(ctx, testBucketName, key, uid, filename, partSize, []int64{4}, 2); err == nil {
+		t.Error("mpuUploadFile out of range part expect error")
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := s3cliTest.MPUUploadFile(canceled, testBucketName, key, uid, filename, partSize, nil, 1); err == nil {
+		t.Error("mpuUploadFile canceled expect error")
+	}
+	if _, err := s3cliTest.MPUUploadFile(ctx, testBucketName, key, uid, filename, partSize, []int64{2, 3}, 2); err != nil {
+		t.Fatal("mpuUploadFile failed: ", err)
+	}
+	if _, err := s3cliTest.MPUUploadFile(ctx, testBucketName, key, uid, filename, partSize, nil, 2); err != nil {
+		t.Fatal("mpuUploadFile failed: ", err)
+	}
+	if _, err := s3cliTest.MPUCompleteAuto(ctx, testBucketName, key, uid); err != nil {
+		t.Fatal("mpuCompleteAuto failed: ", err)
+	}
+	obj, err := s3Backend.GetObject(testBucketName, key, nil)
+	if err != nil {
+		t.Fatal("backend GetObject failed: ", err)
+	}
+	defer obj.Contents.Close()
+	got := &bytes.Buffer{}
+	got.ReadFrom(obj.Contents)
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("content mismatch, size %d, expect %d", got.Len(), len(data))
+	}
+}
+
+func Test_mpuAbort(t *testing.T) {
+	t.Skip("not ready to test")
+	if _, err := s3cliTest.MPUAbort(context.Background(), testBucketName, "key", "upload-id"); err != nil {
+		t.Errorf("mpuAbort failed: %s", err)
+	}
+}
+
+func Test_mpuList(t *testing.T) {
+	t.Skip("not ready to test")
+	if _, err := s3cliTest.MPUList(context.Background(), testBucketName, "prefix"); err != nil {
+		t.Errorf("mpuList failed: %s", err)
+	}
+}
+
+func Test_mpuComplete(t *testing.T) {
+	t.Skip("not ready to test")
+	parts, _ := CompletedParts([]string{"tag1", "tag2"})
+	if _, err := s3cliTest.MPUComplete(context.Background(), testBucketName, "key", "upload-id", parts); err != nil {
+		t.Errorf("mpuComplete failed: %s", err)
+	}
+}
+
+func Test_completedParts(t *testing.T) {
+	parts, err := CompletedParts([]string{"e1", "3:e3", "2:e2"})
+	if err != nil {
+		t.Fatal("CompletedParts failed: ", err)
+	}
+	expect := []string{"1:e1", "2:e2", "3:e3"}
+	for i, p := range parts {
+		if got := fmt.Sprintf("%d:%s", aws.Int64Value(p.PartNumber), aws.StringValue(p.ETag)); got != expect[i] {
+			t.Errorf("expect %s, got %s", expect[i], got)
+		}
+	}
+
+	for _, v := range [][]string{{"1:e1", "1:e2"}, {"x:e1"}, {"0:e1"}, {"1:"}} {
+		if _, err := CompletedParts(v); err == nil {
+			t.Errorf("CompletedParts(%v) expect error", v)
+		}
+	}
+}
+
+func Test_mpuCompleteAuto(t *testing.T) {
+	ctx := context.Background()
+	key := "mpu-auto"
+	cmo, err := s3cliTest.Client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(testBucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		t.Fatal("CreateMultipartUpload failed: ", err)
+	}
+	uid := aws.StringValue(cmo.UploadId)
+	data := make([]byte, s3manager.MinUploadPartSize+10)
+	rand.Read(data)
+	for n, body := range [][]byte{data[:s3manager.MinUploadPartSize], data[s3manager.MinUploadPartSize:]} {
+		if _, err := s3cliTest.Client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Body:       bytes.NewReader(body),
+			Bucket:     aws.String(testBucketName),
+			Key:        aws.String(key),
+			PartNumber: aws.Int64(int64(n + 1)),
+			UploadId:   cmo.UploadId,
+		}); err != nil {
+			t.Fatal("UploadPart failed: ", err)
+		}
+	}
+
+	parts, err := s3cliTest.MPUListParts(ctx, testBucketName, key, uid)
+	if err != nil {
+		t.Errorf("mpuListParts failed: %s", err)
+	} else if len(parts) != 2 {
+		t.Errorf("mpuListParts expect 2 parts, got %d", len(parts))
+	}
+	if _, err := s3cliTest.MPUCompleteAuto(ctx, testBucketName, key, uid); err != nil {
+		t.Fatalf("mpuCompleteAuto failed: %s", err)
+	}
+	obj, err := s3Backend.GetObject(testBucketName, key, nil)
+	if err != nil {
+		t.Fatal("backend GetObject failed: ", err)
+	}
+	defer obj.Contents.Close()
+	if obj.Size != int64(len(data)) {
+		t.Errorf("expect size %d, got %d", len(data), obj.Size)
+	}
+}
+
+func Test_mpuResume(t *testing.T) {
+	bucket := "bucket-mpu-resume"
+	key := "resume/key"
+	if err := s3Backend.CreateBucket(bucket); err != nil {
+		t.Fatal("backend CreateBucket error: ", err)
+	}
+	partSize := int64(s3manager.MinUploadPartSize)
+	data := make([]byte, 2*partSize+1024)
+	rand.Read(data)
+	filename := filepath.Join(t.TempDir(), "big.bin")
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	cmo, err := s3cliTest.Client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		t.Fatal("CreateMultipartUpload failed: ", err)
+	}
+	// part 1 matches the local file, part 2 does not
+	bad := make([]byte, partSize)
+	for n, body := range [][]byte{data[:partSize], bad} {
+		if _, err := s3cliTest.Client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Body:       bytes.NewReader(body),
+			Bucket:     aws.String(bucket),
+			Key:        aws.String(key),
+			PartNumber: aws.Int64(int64(n + 1)),
+			UploadId:   cmo.UploadId,
+		}); err != nil {
+			t.Fatal("UploadPart failed: ", err)
+		}
+	}
+	upload, err := s3cliTest.mpuFindUpload(ctx, bucket, key)
+	if err != nil || upload == nil || aws.StringValue(upload.UploadId) != aws.StringValue(cmo.UploadId) {
+		t.Fatalf("mpuFindUpload expect %s, got %v, %v", aws.StringValue(cmo.UploadId), upload, err)
+	}
+
+	fd, err := os.Open(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	if _, err := s3cliTest.MPUResume(ctx, bucket, key, "", partSize, fd, nil, 2, ObjectOptions{}); err != nil {
+		t.Fatal("mpuResume failed: ", err)
+	}
+
+	obj, err := s3Backend.GetObject(bucket, key, nil)
+	if err != nil {
+		t.Fatal("backend GetObject failed: ", err)
+	}
+	defer obj.Contents.Close()
+	got := &bytes.Buffer{}
+	got.ReadFrom(obj.Contents)
+	if !bytes.Equal(got.Bytes(), data) {
+		t.Errorf("mpuResume content mismatch, size %d, expect %d", got.Len(), len(data))
+	}
+	if upload, _ := s3cliTest.mpuFindUpload(ctx, bucket, key); upload != nil {
+		t.Errorf("upload %s still in progress", aws.StringValue(upload.UploadId))
+	}
+}
+
+func Test_splitBucketObject(t *testing.T) {
+	cases := map[string][2]string{
+		"":                       {"", ""},
+		"/":                      {"", ""},
+		"b/":                     {"b", ""},
+		"bucket/object":          {"bucket", "object"},
+		"b/c.ef/fff/":            {"b", "c.ef/fff/"},
+		"bucket/dir/subdir/file": {"bucket", "dir/subdir/file"},
+	}
+
+	for k, v := range cases {
+		bucket, object := s3cliTest.SplitKeyValue(k, "/")
+		if bucket != v[0] || object != v[1] {
+			t.Errorf("expect: %s, got: %s, %s", v, bucket, object)
+		}
+	}
+}
+
+/*
+aws help
+
+NAME
+       aws -
+
+DESCRIPTION
+       The  AWS  Command  Line  Interface is a unified tool to manage your AWS
+       services.
+
+SYNOPSIS
+          aws [options] <command> <subcommand> [parameters]
+
+       Use aws command help for information on a  specific  command.  Use  aws
+       help  topics  to view a list of available help topics. The synopsis for
+       each command shows its parameters and their usage. Optional  parameters
+       are shown in square brackets.
+
+OPTIONS
+       --debug (boolean)
+
+       Turn on debug logging.
+
+       --endpoint-url (string)
+
+       Override command's default URL with the given URL.
+
+       --no-verify-ssl (boolean)
+
+       By  default, the AWS CLI uses SSL when communicating with AWS services.
+       For each SSL connection, the AWS CLI will verify SSL certificates. This
+       option overrides the default behavior of verifying SSL certificates.
+
+       --no-paginate (boolean)
+
+       Disable automatic pagination.
+
+       --output (string)
+*/
+
+/*
+aws s3api cmd
+
+usage: aws [options] <command> <subcommand> [<subcommand> ...] [parameters]
+To see help text, you can run:
+
+  aws help
+  aws <command> help
+  aws <command> <subcommand> help
+
+aws: error: argument operation: Invalid choice, valid choices are:
+
+abort-multipart-upload                   | complete-multipart-upload
+copy-object                              | create-bucket
+create-multipart-upload                  | delete-bucket
+delete-bucket-analytics-configuration    | delete-bucket-cors
+delete-bucket-encryption                 | delete-bucket-intelligent-tiering-configuration
+delete-bucket-inventory-configuration    | delete-bucket-lifecycle
+delete-bucket-metrics-configuration      | delete-bucket-ownership-controls
+delete-bucket-policy                     | delete-bucket-replication
+delete-bucket-tagging                    | delete-bucket-website
+delete-object                            | delete-object-tagging
+delete-objects                           | delete-public-access-block
+get-bucket-accelerate-configuration      | get-bucket-acl
+get-bucket-analytics-configuration       | get-bucket-cors
+get-bucket-encryption                    | get-bucket-intelligent-tiering-configuration
+get-bucket-inventory-configuration       | get-bucket-lifecycle
+get-bucket-lifecycle-configuration       | get-bucket-location
+get-bucket-logging                       | get-bucket-metrics-configuration
+get-bucket-notification                  | get-bucket-notification-configuration
+get-bucket-ownership-controls            | get-bucket-policy
+get-bucket-policy-status                 | get-bucket-replication
+get-bucket-request-payment               | get-bucket-tagging
+get-bucket-versioning                    | get-bucket-website
+get-object                               | get-object-acl
+get-object-legal-hold                    | get-object-lock-configuration
+get-object-retention                     | get-object-tagging
+get-object-torrent                       | get-public-access-block
+head-bucket                              | head-object
+list-bucket-analytics-configurations     | list-bucket-intelligent-tiering-configurations
+list-bucket-inventory-configurations     | list-bucket-metrics-configurations
+list-buckets                             | list-multipart-uploads
+list-object-versions                     | list-objects
+list-objects-v2                          | list-parts
+put-bucket-accelerate-configuration      | put-bucket-acl
+put-bucket-analytics-configuration       | put-bucket-cors
+put-bucket-encryption                    | put-bucket-intelligent-tiering-configuration
+put-bucket-inventory-configuration       | put-bucket-lifecycle
+put-bucket-lifecycle-configuration       | put-bucket-logging
+put-bucket-metrics-configuration         | put-bucket-notification
+put-bucket-notification-configuration    | put-bucket-ownership-controls
+put-bucket-policy                        | put-bucket-replication
+put-bucket-request-payment               | put-bucket-tagging
+put-bucket-versioning                    | put-bucket-website
+put-object                               | put-object-acl
+put-object-legal-hold                    | put-object-lock-configuration
+put-object-retention                     | put-object-tagging
+put-public-access-block                  | restore-object
+select-object-content                    | upload-part
+upload-part-copy                         | write-get-object-response
+wait                                     | help
+*/
@@ -0,0 +1,608 @@
+package s3cli
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+const (
+	syncActionUpload   = "upload"
+	syncActionDownload = "download"
+	syncActionDelete   = "delete"
+	syncActionCopy     = "copy"
+)
+
+var syncSchema = schema{
+	columns: []string{"Action", "Source", "Target", "Size", "DryRun", "Error"},
+	simple:  []string{"Action", "Target", "Error"},
+}
+
+// SyncOptions control a sync between a local directory and Bucket/prefix(or two Bucket/prefixes)
+type SyncOptions struct {
+	Delete      bool // delete destination files/Objects not exist in source
+	DryRun      bool // only show what would be done
+	Filter      PathFilter
+	Concurrency int
+	Source      *S3Cli // source S3 service of a Bucket to Bucket sync, nil is the same service
+
+	PartSize        int64 // part size of a Object streamed from another S3 service
+	PartConcurrency int   // concurrent parts of a streamed or multipart copied Object
+}
+
+// SyncAction record a sync action and its result
+type SyncAction struct {
+	Action string `json:"action"`
+	Source string `json:"source,omitempty"`
+	Target string `json:"target"`
+	Size   int64  `json:"size"`
+	DryRun bool   `json:"dryRun,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// localFile is a regular file found under a local directory
+type localFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// PathFilter filter relative paths with --exclude and --include glob patterns,
+// a path matching any include pattern is always synced, otherwise a path matching
+// any exclude pattern is skipped, if only include patterns are given every
+// path not matching them is skipped
+type PathFilter struct {
+	Exclude []string
+	Include []string
+}
+
+// validate check all glob patterns
+func (f PathFilter) validate() error {
+	for _, p := range append(append([]string{}, f.Exclude...), f.Include...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("invalid pattern %s: %w", p, err)
+		}
+	}
+	return nil
+}
+
+// match report whether a relative(slash separated) path should be synced
+func (f PathFilter) match(rel string) bool {
+	if matchAny(f.Include, rel) {
+		return true
+	}
+	if matchAny(f.Exclude, rel) {
+		return false
+	}
+	return len(f.Include) == 0
+}
+
+// matchAny report whether the relative path, its base name or
+// any of its parent directories matches any pattern
+func matchAny(patterns []string, rel string) bool {
+	names := []string{rel}
+	for dir := rel; ; {
+		names = append(names, path.Base(dir))
+		dir = path.Dir(dir)
+		if dir == "." || dir == "/" {
+			break
+		}
+		names = append(names, dir)
+	}
+	for _, p := range patterns {
+		p = strings.TrimSuffix(p, "/")
+		for _, name := range names {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// s3ignoreFile list glob patterns(one per line) to skip in recursive upload
+const s3ignoreFile = ".s3ignore"
+
+// loadIgnoreFile read glob patterns from an ignore file,
+// blank lines and lines start with '#' are skipped, a not exist file has no patterns
+func loadIgnoreFile(filename string) ([]string, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	patterns := []string{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, nil
+}
+
+// S3Scheme mark a sync argument as Bucket/prefix, e.g. s3://bucket-name/prefix
+const S3Scheme = "s3://"
+
+// syncPath is a sync argument with its direction resolved
+type syncPath struct {
+	path  string
+	local bool
+}
+
+// markedPath report whether a sync argument is marked as local path(starts with '.' or is absolute)
+// or Bucket/prefix(starts with s3://), the returned path has the scheme trimmed
+func markedPath(p string) (path string, local, marked bool) {
+	if strings.HasPrefix(p, S3Scheme) {
+		return strings.TrimPrefix(p, S3Scheme), false, true
+	}
+	if strings.HasPrefix(p, ".") || filepath.IsAbs(p) {
+		return p, true, true
+	}
+	return p, false, false
+}
+
+// resolveSyncPaths resolve the direction of sync arguments, an unmarked argument is the opposite
+// of the marked one, refuse to guess if both are unmarked or an unmarked Bucket/prefix exists locally,
+// both are Bucket/prefix if srcRemote(source S3 service set)
+func resolveSyncPaths(src, dst string, srcRemote bool) (syncPath, syncPath, error) {
+	s, sLocal, sMarked := markedPath(src)
+	d, dLocal, dMarked := markedPath(dst)
+	if srcRemote {
+		// both are Bucket/prefix with source S3 service
+		if sLocal || dLocal {
+			return syncPath{}, syncPath{}, fmt.Errorf("source S3 service only apply to Bucket to Bucket sync")
+		}
+		if !dMarked && existsLocally(d) {
+			return syncPath{}, syncPath{}, ambiguousPathError(d)
+		}
+		return syncPath{s, false}, syncPath{d, false}, nil
+	}
+	switch {
+	case !sMarked && !dMarked:
+		return syncPath{}, syncPath{}, fmt.Errorf("ambiguous sync direction of %s and %s, mark Bucket/prefix with %s or local path with ./", src, dst, S3Scheme)
+	case !sMarked:
+		sLocal = !dLocal
+		if !sLocal && existsLocally(s) {
+			return syncPath{}, syncPath{}, ambiguousPathError(s)
+		}
+	case !dMarked:
+		dLocal = !sLocal
+		if !dLocal && existsLocally(d) {
+			return syncPath{}, syncPath{}, ambiguousPathError(d)
+		}
+	}
+	if sLocal && dLocal {
+		return syncPath{}, syncPath{}, fmt.Errorf("both source(%s) and destination(%s) are local paths", src, dst)
+	}
+	return syncPath{s, sLocal}, syncPath{d, dLocal}, nil
+}
+
+// existsLocally report whether p exists locally
+func existsLocally(p string) bool {
+	_, err := os.Stat(p)
+	return err == nil
+}
+
+// ambiguousPathError is the error of an unmarked Bucket/prefix exists locally
+func ambiguousPathError(p string) error {
+	return fmt.Errorf("ambiguous sync path %s exists locally, use ./%s for local path or %s%s for Bucket/prefix", p, p, S3Scheme, p)
+}
+
+// localPath join a relative(slash separated) path to root,
+// refuse paths escaping root directory(through '..')
+func localPath(root, rel string) (string, error) {
+	name := filepath.Join(root, filepath.FromSlash(rel))
+	r, err := filepath.Rel(root, name)
+	if err != nil || r == ".." || strings.HasPrefix(r, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("invalid path %s escapes %s", rel, root)
+	}
+	return name, nil
+}
+
+// walkLocal find all regular files under root, keyed by slash separated relative path
+func walkLocal(root string, filter PathFilter) (map[string]localFile, error) {
+	files := map[string]localFile{}
+	err := filepath.Walk(root, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !filter.match(rel) {
+			return nil
+		}
+		files[rel] = localFile{
+			path:    p,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+		return nil
+	})
+	return files, err
+}
+
+// walkRemote list all Objects with prefix, keyed by the key relative to prefix
+func (sc *S3Cli) walkRemote(ctx context.Context, bucket, prefix string, filter PathFilter) (map[string]*s3.Object, error) {
+	objects := map[string]*s3.Object{}
+	listInput := &s3.ListObjectsV2Input{
+		Bucket: aws.String(bucket),
+	}
+	if prefix != "" {
+		listInput.SetPrefix(prefix)
+	}
+	err := sc.Client.ListObjectsV2PagesWithContext(ctx, listInput, func(p *s3.ListObjectsV2Output, last bool) bool {
+		for _, obj := range p.Contents {
+			rel := strings.TrimPrefix(aws.StringValue(obj.Key), prefix)
+			// skip directory marker
+			if rel == "" || strings.HasSuffix(rel, "/") {
+				continue
+			}
+			if !filter.match(rel) {
+				continue
+			}
+			objects[rel] = obj
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list objects failed: %w", err)
+	}
+	return objects, nil
+}
+
+// fileMD5 return the hex md5 of a local file
+func fileMD5(filename string) (string, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer fd.Close()
+	h := md5.New()
+	if _, err := io.Copy(h, fd); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// sameContent compare a local file with a Object by size, mtime and ETag/MD5,
+// the md5 is only computed if the source side(upload: local file) is newer
+func sameContent(f localFile, obj *s3.Object, upload bool) bool {
+	if f.size != aws.Int64Value(obj.Size) {
+		return false
+	}
+	mtime := aws.TimeValue(obj.LastModified)
+	if upload && !f.modTime.After(mtime) {
+		return true
+	}
+	if !upload && !mtime.After(f.modTime) {
+		return true
+	}
+	etag := strings.Trim(aws.StringValue(obj.ETag), "\"")
+	if len(etag) != md5.Size*2 || strings.Contains(etag, "-") {
+		// multipart ETag is not the md5 of Object
+		return false
+	}
+	sum, err := fileMD5(f.path)
+	if err != nil {
+		return false
+	}
+	return sum == etag
+}
+
+// sameObject compare two Objects by size and ETag,
+// Objects with different ETag(e.g. different part size) are same if destination is not older
+func sameObject(src, dst *s3.Object) bool {
+	if aws.Int64Value(src.Size) != aws.Int64Value(dst.Size) {
+		return false
+	}
+	if aws.StringValue(src.ETag) == aws.StringValue(dst.ETag) {
+		return true
+	}
+	return !aws.TimeValue(src.LastModified).After(aws.TimeValue(dst.LastModified))
+}
+
+// SyncDir sync a local directory and Bucket/prefix in either direction,
+// or sync two Bucket/prefixes(opt.Source is the S3 service of src), see resolveSyncPaths for
+// how src and dst are told apart, only files/Objects differ in size, mtime or ETag/MD5 are transferred
+func (sc *S3Cli) SyncDir(ctx context.Context, src, dst string, opt SyncOptions) ([]SyncAction, error) {
+	if err := opt.Filter.validate(); err != nil {
+		return nil, err
+	}
+	srcPath, dstPath, err := resolveSyncPaths(src, dst, opt.Source != nil)
+	if err != nil {
+		return nil, err
+	}
+	src, dst = srcPath.path, dstPath.path
+	srcLocal, dstLocal := srcPath.local, dstPath.local
+	if !srcLocal && !dstLocal {
+		return sc.syncBucket(ctx, src, dst, opt)
+	}
+
+	upload := srcLocal
+	dir, bucketPrefix := src, dst
+	if !upload {
+		dir, bucketPrefix = dst, src
+	}
+	bucket, prefix := sc.SplitKeyValue(bucketPrefix, "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("unknown bucket <bucket/prefix>(%s)", bucketPrefix)
+	}
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+
+	if upload {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory", dir)
+		}
+	} else if !opt.DryRun {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+
+	files := map[string]localFile{}
+	if _, err := os.Stat(dir); err == nil {
+		var err error
+		files, err = walkLocal(dir, opt.Filter)
+		if err != nil {
+			return nil, err
+		}
+	}
+	objects, err := sc.walkRemote(ctx, bucket, prefix, opt.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []SyncAction{}
+	if upload {
+		for rel, f := range files {
+			if obj, ok := objects[rel]; ok && sameContent(f, obj, true) {
+				continue
+			}
+			actions = append(actions, SyncAction{Action: syncActionUpload, Source: f.path, Target: bucket + "/" + prefix + rel, Size: f.size})
+		}
+		if opt.Delete {
+			for rel, obj := range objects {
+				if _, ok := files[rel]; !ok {
+					actions = append(actions, SyncAction{Action: syncActionDelete, Target: bucket + "/" + prefix + rel, Size: aws.Int64Value(obj.Size)})
+				}
+			}
+		}
+	} else {
+		for rel, obj := range objects {
+			if f, ok := files[rel]; ok && sameContent(f, obj, false) {
+				continue
+			}
+			name, err := localPath(dir, rel)
+			if err != nil {
+				actions = append(actions, SyncAction{Action: syncActionDownload, Source: bucket + "/" + prefix + rel, Error: err.Error()})
+				continue
+			}
+			actions = append(actions, SyncAction{Action: syncActionDownload, Source: bucket + "/" + prefix + rel, Target: name, Size: aws.Int64Value(obj.Size)})
+		}
+		if opt.Delete {
+			for rel, f := range files {
+				if _, ok := objects[rel]; !ok {
+					actions = append(actions, SyncAction{Action: syncActionDelete, Target: f.path, Size: f.size})
+				}
+			}
+		}
+	}
+	return sc.syncFinish(ctx, actions, upload, opt)
+}
+
+// syncBucket sync Bucket/prefix(src) to another Bucket/prefix(dst),
+// Objects are copied server-side, or streamed if opt.Source is another S3 service
+func (sc *S3Cli) syncBucket(ctx context.Context, src, dst string, opt SyncOptions) ([]SyncAction, error) {
+	source := opt.Source
+	if source == nil {
+		source = sc
+	}
+	srcBucket, srcPrefix := sc.SplitKeyValue(src, "/")
+	dstBucket, dstPrefix := sc.SplitKeyValue(dst, "/")
+	if srcBucket == "" || dstBucket == "" {
+		return nil, fmt.Errorf("unknown bucket <bucket/prefix>(%s, %s)", src, dst)
+	}
+	if srcPrefix != "" && !strings.HasSuffix(srcPrefix, "/") {
+		srcPrefix += "/"
+	}
+	if dstPrefix != "" && !strings.HasSuffix(dstPrefix, "/") {
+		dstPrefix += "/"
+	}
+	if source == sc && srcBucket == dstBucket && (strings.HasPrefix(dstPrefix, srcPrefix) || strings.HasPrefix(srcPrefix, dstPrefix)) {
+		return nil, fmt.Errorf("source(%s) and destination(%s) overlap", src, dst)
+	}
+
+	srcObjects, err := source.walkRemote(ctx, srcBucket, srcPrefix, opt.Filter)
+	if err != nil {
+		return nil, fmt.Errorf("source: %w", err)
+	}
+	dstObjects, err := sc.walkRemote(ctx, dstBucket, dstPrefix, opt.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	actions := []SyncAction{}
+	for rel, obj := range srcObjects {
+		if o, ok := dstObjects[rel]; ok && sameObject(obj, o) {
+			continue
+		}
+		actions = append(actions, SyncAction{Action: syncActionCopy, Source: srcBucket + "/" + srcPrefix + rel, Target: dstBucket + "/" + dstPrefix + rel, Size: aws.Int64Value(obj.Size)})
+	}
+	if opt.Delete {
+		for rel, obj := range dstObjects {
+			if _, ok := srcObjects[rel]; !ok {
+				actions = append(actions, SyncAction{Action: syncActionDelete, Target: dstBucket + "/" + dstPrefix + rel, Size: aws.Int64Value(obj.Size)})
+			}
+		}
+	}
+	opt.Source = source
+	return sc.syncFinish(ctx, actions, true, opt)
+}
+
+// syncFinish run(not dry-run) sync actions and return them with their results,
+// upload means the destination of actions is Bucket
+func (sc *S3Cli) syncFinish(ctx context.Context, actions []SyncAction, upload bool, opt SyncOptions) ([]SyncAction, error) {
+	sort.Slice(actions, func(i, j int) bool {
+		if actions[i].Action != actions[j].Action {
+			return actions[i].Action > actions[j].Action
+		}
+		return actions[i].Target < actions[j].Target
+	})
+
+	if opt.DryRun {
+		for i := range actions {
+			actions[i].DryRun = true
+		}
+	} else {
+		sc.syncRun(ctx, actions, upload, opt)
+	}
+
+	failed := 0
+	for _, a := range actions {
+		if a.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return actions, fmt.Errorf("%d of %d sync actions failed", failed, len(actions))
+	}
+	return actions, nil
+}
+
+// syncRun do all sync actions with a worker pool(opt.Concurrency),
+// upload means the destination of actions is Bucket, opt.Source is the S3 service of copy actions
+func (sc *S3Cli) syncRun(ctx context.Context, actions []SyncAction, upload bool, opt SyncOptions) {
+	concurrency := opt.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	uploader := s3manager.NewUploaderWithClient(sc.Client)
+	index := make(chan int)
+	wg := sync.WaitGroup{}
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range index {
+				a := &actions[i]
+				var err error
+				switch a.Action {
+				case syncActionUpload:
+					bucket, key := sc.SplitKeyValue(a.Target, "/")
+					err = sc.syncUpload(ctx, uploader, bucket, key, a.Source)
+				case syncActionDownload:
+					if a.Error != "" {
+						continue
+					}
+					bucket, key := sc.SplitKeyValue(a.Source, "/")
+					err = sc.downloadObject(ctx, bucket, key, a.Target)
+				case syncActionCopy:
+					err = sc.syncCopy(ctx, a, opt)
+				case syncActionDelete:
+					if upload {
+						bucket, key := sc.SplitKeyValue(a.Target, "/")
+						_, err = sc.Client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+							Bucket: aws.String(bucket),
+							Key:    aws.String(key),
+						})
+					} else {
+						err = os.Remove(a.Target)
+					}
+				}
+				if err != nil {
+					a.Error = err.Error()
+				}
+			}
+		}()
+	}
+	for i := range actions {
+		if ctx.Err() != nil {
+			actions[i].Error = ctx.Err().Error()
+			continue
+		}
+		index <- i
+	}
+	close(index)
+	wg.Wait()
+}
+
+// syncUpload upload a local file to bucket/key
+func (sc *S3Cli) syncUpload(ctx context.Context, uploader *s3manager.Uploader, bucket, key, filename string) error {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	ui := &s3manager.UploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+		Body:   fd,
+	}
+	if contentType := mime.TypeByExtension(filepath.Ext(filename)); contentType != "" {
+		ui.ContentType = aws.String(contentType)
+	}
+	_, err = uploader.UploadWithContext(ctx, ui)
+	return err
+}
+
+// syncCopy copy a Object of opt.Source to sc with opt.PartSize and opt.PartConcurrency,
+// a Object of the same S3 service is copied server-side
+func (sc *S3Cli) syncCopy(ctx context.Context, a *SyncAction, opt SyncOptions) error {
+	srcBucket, srcKey := sc.SplitKeyValue(a.Source, "/")
+	dstBucket, dstKey := sc.SplitKeyValue(a.Target, "/")
+	if source := opt.Source; source != nil && source != sc {
+		_, err := sc.streamCopy(ctx, source, srcBucket, srcKey, dstBucket, dstKey, "", nil, opt.PartSize, opt.PartConcurrency, ObjectOptions{})
+		return err
+	}
+	if a.Size > maxCopyObjectSize {
+		head, err := sc.Client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+			Bucket: aws.String(srcBucket),
+			Key:    aws.String(srcKey),
+		})
+		if err != nil {
+			return fmt.Errorf("head object failed: %w", err)
+		}
+		cmi := newCopyMultipartInput(head, dstBucket, dstKey, "", nil)
+		_, err = sc.copyObjectMultipart(ctx, srcBucket, srcKey, aws.Int64Value(head.ContentLength), cmi, DefaultCopyPartSize, opt.PartConcurrency)
+		return err
+	}
+	_, err := sc.Client.CopyObjectWithContext(ctx, &s3.CopyObjectInput{
+		CopySource: aws.String(copySource(srcBucket, srcKey)),
+		Bucket:     aws.String(dstBucket),
+		Key:        aws.String(dstKey),
+	})
+	if err != nil {
+		return fmt.Errorf("copy object failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+func Test_pathFilter(t *testing.T) {
+	cases := []struct {
+		filter PathFilter
+		path   string
+		expect bool
+	}{
+		{PathFilter{}, "a/b.txt", true},
+		{PathFilter{Exclude: []string{"*.tmp"}}, "a/b.tmp", false},
+		{PathFilter{Exclude: []string{"*.tmp"}}, "a/b.txt", true},
+		{PathFilter{Exclude: []string{"*.tmp"}, Include: []string{"keep.tmp"}}, "a/keep.tmp", true},
+		{PathFilter{Include: []string{"*.jpg"}}, "a/b.txt", false},
+		{PathFilter{Include: []string{"*.jpg"}}, "a/b.jpg", true},
+		{PathFilter{Exclude: []string{"a/*"}}, "a/b.jpg", false},
+	}
+	for _, c := range cases {
+		if got := c.filter.match(c.path); got != c.expect {
+			t.Errorf("filter %+v match %s expect: %v, got: %v", c.filter, c.path, c.expect, got)
+		}
+	}
+	if err := (PathFilter{Exclude: []string{"[a-"}}).validate(); err == nil {
+		t.Errorf("validate invalid pattern should fail")
+	}
+}
+
+func Test_localPath(t *testing.T) {
+	cases := map[string]bool{
+		"a/b":       true,
+		"a/../b":    true,
+		"../b":      false,
+		"a/../../b": false,
+		"..":        false,
+	}
+	for rel, ok := range cases {
+		_, err := localPath("root", rel)
+		if (err == nil) != ok {
+			t.Errorf("localPath %s expect ok: %v, got error: %v", rel, ok, err)
+		}
+	}
+}
+
+func Test_resolveSyncPaths(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	// a local directory named like a Bucket
+	if err := os.Mkdir("bucket-name", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		src, dst           string
+		srcRemote          bool
+		srcLocal, dstLocal bool
+		ok                 bool
+	}{
+		{src: "./dir", dst: "bucket/prefix", srcLocal: true, ok: true},
+		{src: "bucket/prefix", dst: "./backup", dstLocal: true, ok: true},
+		{src: "s3://bucket/prefix", dst: "backup", dstLocal: true, ok: true},
+		{src: "backup", dst: "s3://bucket/prefix", srcLocal: true, ok: true},
+		{src: "s3://bucket-a/prefix", dst: "s3://bucket-b/prefix", ok: true},
+		{src: "./dir", dst: "s3://bucket-name", srcLocal: true, ok: true},
+		{src: "bucket-a/prefix", dst: "bucket-b/prefix", srcRemote: true, ok: true},
+		// not yet existing local directory
+		{src: "bucket/prefix", dst: "backup"},
+		// local directory named like a Bucket
+		{src: "./dir", dst: "bucket-name"},
+		{src: "bucket/prefix", dst: "bucket-name", srcRemote: true},
+		{src: "./dir", dst: "./backup"},
+		{src: "bucket/prefix", dst: "./backup", srcRemote: true},
+	}
+	for _, c := range cases {
+		src, dst, err := resolveSyncPaths(c.src, c.dst, c.srcRemote)
+		if (err == nil) != c.ok {
+			t.Errorf("resolveSyncPaths %s %s expect ok: %v, got error: %v", c.src, c.dst, c.ok, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if src.local != c.srcLocal || dst.local != c.dstLocal {
+			t.Errorf("resolveSyncPaths %s %s expect local: %v %v, got: %v %v", c.src, c.dst, c.srcLocal, c.dstLocal, src.local, dst.local)
+		}
+		if strings.HasPrefix(src.path, S3Scheme) || strings.HasPrefix(dst.path, S3Scheme) {
+			t.Errorf("resolveSyncPaths %s %s should trim %s", c.src, c.dst, S3Scheme)
+		}
+	}
+}
+
+func Test_syncDir(t *testing.T) {
+	ctx := context.Background()
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"a.txt":     "aaaa",
+		"sub/b.txt": "bbbb",
+		"c.tmp":     "cccc",
+	}
+	for name, content := range files {
+		p := filepath.Join(srcDir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	bucket := "bucket-sync"
+	if err := s3Backend.CreateBucket(bucket); err != nil {
+		t.Fatal("backend CreateBucket error: ", err)
+	}
+	dst := bucket + "/syncDir"
+	opt := SyncOptions{
+		Filter:      PathFilter{Exclude: []string{"*.tmp"}},
+		Concurrency: 2,
+	}
+
+	opt.DryRun = true
+	actions, err := s3cliTest.SyncDir(ctx, srcDir, dst, opt)
+	if err != nil {
+		t.Fatalf("syncDir dry-run failed: %s", err)
+	}
+	if len(actions) != 2 || actions[0].Action != syncActionUpload || !actions[0].DryRun {
+		t.Errorf("syncDir dry-run expect 2 upload actions, got %v", actions)
+	}
+	if _, err := s3Backend.HeadObject(bucket, "syncDir/a.txt"); err == nil {
+		t.Errorf("syncDir dry-run should not upload")
+	}
+
+	opt.DryRun = false
+	if _, err := s3cliTest.SyncDir(ctx, srcDir, dst, opt); err != nil {
+		t.Fatalf("syncDir upload failed: %s", err)
+	}
+	for _, k := range []string{"syncDir/a.txt", "syncDir/sub/b.txt"} {
+		if _, err := s3Backend.HeadObject(bucket, k); err != nil {
+			t.Errorf("syncDir upload backend HeadObject %s failed: %s", k, err)
+		}
+	}
+	if _, err := s3Backend.HeadObject(bucket, "syncDir/c.tmp"); err == nil {
+		t.Errorf("syncDir upload excluded file c.tmp")
+	}
+
+	// Object not exist in local directory
+	_, err = s3Backend.PutObject(bucket, "syncDir/extra", nil, bytes.NewReader(testObjectContent), int64(len(testObjectContent)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opt.Delete = true
+	if _, err := s3cliTest.SyncDir(ctx, srcDir, dst, opt); err != nil {
+		t.Fatalf("syncDir upload delete failed: %s", err)
+	}
+	if _, err := s3Backend.HeadObject(bucket, "syncDir/extra"); err == nil {
+		t.Errorf("syncDir --delete should delete syncDir/extra")
+	}
+
+	dstDir := filepath.Join(t.TempDir(), "download")
+	if _, err := s3cliTest.SyncDir(ctx, dst, dstDir, opt); err != nil {
+		t.Fatalf("syncDir download failed: %s", err)
+	}
+	for _, name := range []string{"a.txt", "sub/b.txt"} {
+		data, err := os.ReadFile(filepath.Join(dstDir, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("syncDir download read %s failed: %s", name, err)
+			continue
+		}
+		if string(data) != files[name] {
+			t.Errorf("syncDir download %s expect: %s, got: %s", name, files[name], data)
+		}
+	}
+
+	// unchanged files should not be downloaded again
+	stale := filepath.Join(dstDir, "stale")
+	if err := os.WriteFile(stale, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dstDir, "a.txt"), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s3cliTest.SyncDir(ctx, dst, dstDir, opt); err != nil {
+		t.Fatalf("syncDir download again failed: %s", err)
+	}
+	if _, err := os.Stat(stale); err == nil {
+		t.Errorf("syncDir download --delete should delete %s", stale)
+	}
+
+	fd, err := os.Open(filepath.Join(dstDir, "a.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	data, _ := io.ReadAll(fd)
+	if string(data) != files["a.txt"] {
+		t.Errorf("syncDir download a.txt expect: %s, got: %s", files["a.txt"], data)
+	}
+}
+
+func Test_syncBucket(t *testing.T) {
+	ctx := context.Background()
+	srcBucket, dstBucket := "bucket-sync-src", "bucket-sync-dst"
+	for _, b := range []string{srcBucket, dstBucket} {
+		if err := s3Backend.CreateBucket(b); err != nil {
+			t.Fatal("backend CreateBucket error: ", err)
+		}
+	}
+	meta := map[string]string{"Content-Type": "text/plain", "X-Amz-Meta-Owner": "sync"}
+	for _, k := range []string{"p/a", "p/sub/b"} {
+		if _, err := s3Backend.PutObject(srcBucket, k, meta, bytes.NewReader(testObjectContent), int64(len(testObjectContent))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// another S3Cli(same fake server) streams Objects
+	source := &S3Cli{
+		Endpoint:  s3cliTest.Endpoint,
+		AccessKey: s3cliTest.AccessKey,
+		SecretKey: s3cliTest.SecretKey,
+		Region:    s3cliTest.Region,
+		PathStyle: true,
+	}
+	client, err := NewS3Client(source)
+	if err != nil {
+		t.Fatal("newS3Client failed: ", err)
+	}
+	source.Client = client
+
+	for _, src := range []*S3Cli{nil, source} {
+		prefix := "server-side/"
+		if src != nil {
+			prefix = "stream/"
+		}
+		opt := SyncOptions{Concurrency: 2, Source: src, PartSize: s3manager.MinUploadPartSize, PartConcurrency: 2}
+		if _, err := s3cliTest.SyncDir(ctx, S3Scheme+srcBucket+"/p", S3Scheme+dstBucket+"/"+prefix, opt); err != nil {
+			t.Fatalf("syncDir bucket failed: %s", err)
+		}
+		for _, k := range []string{"a", "sub/b"} {
+			obj, err := s3Backend.HeadObject(dstBucket, prefix+k)
+			if err != nil {
+				t.Errorf("syncDir bucket backend HeadObject %s failed: %s", prefix+k, err)
+				continue
+			}
+			if obj.Metadata["X-Amz-Meta-Owner"] != "sync" || obj.Metadata["Content-Type"] != "text/plain" {
+				t.Errorf("syncDir bucket %s metadata not preserved: %v", prefix+k, obj.Metadata)
+			}
+		}
+	}
+
+	if _, err := s3cliTest.SyncDir(ctx, S3Scheme+srcBucket+"/p", S3Scheme+srcBucket+"/p/sub", SyncOptions{}); err == nil {
+		t.Errorf("syncDir bucket into source prefix should fail")
+	}
+}
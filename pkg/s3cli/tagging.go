@@ -0,0 +1,291 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+const (
+	maxObjectTags = 10
+	maxBucketTags = 50
+)
+
+var tagSchema = schema{
+	columns:      []string{"Key", "Value"},
+	simple:       []string{"Key", "Value"},
+	simpleFormat: "%v=%v",
+}
+
+// ObjectOptions is the optional settings of a new Object(upload, mpu and copy)
+type ObjectOptions struct {
+	Tagging string // tags in x-amz-tagging format(k1=v1&k2=v2)
+	// COPY or REPLACE tags of copy source, default REPLACE if Tagging is set, otherwise COPY
+	TaggingDirective string
+
+	ObjectLockOptions
+	ObjectWebsiteOptions
+}
+
+// validate validate the settings of a new Object
+func (o ObjectOptions) validate() error {
+	if err := o.ObjectLockOptions.validate(); err != nil {
+		return err
+	}
+	return o.ObjectWebsiteOptions.validate()
+}
+
+// tagging return the x-amz-tagging of o, nil if no tag
+func (o ObjectOptions) tagging() *string {
+	if o.Tagging == "" {
+		return nil
+	}
+	return aws.String(o.Tagging)
+}
+
+// taggingDirective return the tagging directive of a copy
+func (o ObjectOptions) taggingDirective() (string, error) {
+	switch strings.ToUpper(o.TaggingDirective) {
+	case "":
+		if o.Tagging != "" {
+			return s3.TaggingDirectiveReplace, nil
+		}
+		return s3.TaggingDirectiveCopy, nil
+	case s3.TaggingDirectiveCopy:
+		if o.Tagging != "" {
+			return "", errors.New("tags require REPLACE tagging directive")
+		}
+		return s3.TaggingDirectiveCopy, nil
+	case s3.TaggingDirectiveReplace:
+		return s3.TaggingDirectiveReplace, nil
+	}
+	return "", fmt.Errorf("invalid tagging directive %s(COPY or REPLACE)", o.TaggingDirective)
+}
+
+// ParseTags parse tags of format Key=Value
+func ParseTags(kvs []string) ([]*s3.Tag, error) {
+	tags := make([]*s3.Tag, 0, len(kvs))
+	seen := map[string]bool{}
+	for _, kv := range kvs {
+		n := strings.Index(kv, "=")
+		if n < 1 {
+			return nil, fmt.Errorf("invalid tag %q(format Key=Value)", kv)
+		}
+		k, v := kv[:n], kv[n+1:]
+		if utf8.RuneCountInString(k) > 128 {
+			return nil, fmt.Errorf("tag key %s longer than 128 characters", k)
+		}
+		if utf8.RuneCountInString(v) > 256 {
+			return nil, fmt.Errorf("tag value of %s longer than 256 characters", k)
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("duplicate tag key %s", k)
+		}
+		seen[k] = true
+		tags = append(tags, &s3.Tag{Key: aws.String(k), Value: aws.String(v)})
+	}
+	return tags, nil
+}
+
+// ParseTagging parse Object tags of format Key=Value to x-amz-tagging format
+func ParseTagging(kvs []string) (string, error) {
+	tags, err := ParseTags(kvs)
+	if err != nil {
+		return "", err
+	}
+	if len(tags) > maxObjectTags {
+		return "", fmt.Errorf("%d tags, a Object has at most %d tags", len(tags), maxObjectTags)
+	}
+	return encodeTagSet(tags), nil
+}
+
+// printTags print a tag set as records
+func (sc *S3Cli) printTags(tags []*s3.Tag) error {
+	p := sc.newPrinter(tagSchema)
+	for _, t := range tags {
+		if err := p.add(aws.StringValue(t.Key), aws.StringValue(t.Value)); err != nil {
+			return err
+		}
+	}
+	return p.flush()
+}
+
+// BucketTaggingGet get a Bucket's tags
+func (sc *S3Cli) BucketTaggingGet(ctx context.Context, bucket string) (*s3.GetBucketTaggingOutput, error) {
+	req, resp := sc.Client.GetBucketTaggingRequest(&s3.GetBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get bucket tagging failed: %w", err)
+	}
+	return resp, nil
+}
+
+// BucketTaggingPut put(replace) a Bucket's tags
+func (sc *S3Cli) BucketTaggingPut(ctx context.Context, bucket string, tags []*s3.Tag) error {
+	if len(tags) > maxBucketTags {
+		return fmt.Errorf("%d tags, a Bucket has at most %d tags", len(tags), maxBucketTags)
+	}
+	req, resp := sc.Client.PutBucketTaggingRequest(&s3.PutBucketTaggingInput{
+		Bucket:  aws.String(bucket),
+		Tagging: &s3.Tagging{TagSet: tags},
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put bucket tagging failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// BucketTaggingDelete delete a Bucket's tags
+func (sc *S3Cli) BucketTaggingDelete(ctx context.Context, bucket string) error {
+	req, resp := sc.Client.DeleteBucketTaggingRequest(&s3.DeleteBucketTaggingInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("delete bucket tagging failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
+
+// ObjectTaggingGet get a Object's(or its version's) tags
+func (sc *S3Cli) ObjectTaggingGet(ctx context.Context, bucket, key, version string) (*s3.GetObjectTaggingOutput, error) {
+	in := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	req, resp := sc.Client.GetObjectTaggingRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get object tagging failed: %w", err)
+	}
+	return resp, nil
+}
+
+// ObjectTaggingPut put(replace) a Object's(or its version's) tags
+func (sc *S3Cli) ObjectTaggingPut(ctx context.Context, bucket, key, version string, tags []*s3.Tag) error {
+	if len(tags) > maxObjectTags {
+		return fmt.Errorf("%d tags, a Object has at most %d tags", len(tags), maxObjectTags)
+	}
+	in := &s3.PutObjectTaggingInput{
+		Bucket:  aws.String(bucket),
+		Key:     aws.String(key),
+		Tagging: &s3.Tagging{TagSet: tags},
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	req, resp := sc.Client.PutObjectTaggingRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put object tagging failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ObjectTaggingDelete delete a Object's(or its version's) tags
+func (sc *S3Cli) ObjectTaggingDelete(ctx context.Context, bucket, key, version string) error {
+	in := &s3.DeleteObjectTaggingInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	}
+	if version != "" {
+		in.VersionId = aws.String(version)
+	}
+	req, resp := sc.Client.DeleteObjectTaggingRequest(in)
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("delete object tagging failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
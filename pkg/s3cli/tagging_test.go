@@ -0,0 +1,129 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+// headerRecorder record request headers of a method before serving it with next,
+// and add response headers to the responses
+type headerRecorder struct {
+	mu          sync.Mutex
+	headers     map[string]http.Header
+	respHeaders http.Header
+	next        http.Handler
+}
+
+func (h *headerRecorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mu.Lock()
+	h.headers[r.Method] = r.Header.Clone()
+	for k, vs := range h.respHeaders {
+		w.Header()[k] = vs
+	}
+	h.mu.Unlock()
+	h.next.ServeHTTP(w, r)
+}
+
+// newHeaderRecorderCli create a S3Cli of a gofakes3 server(closed on cleanup) with bucket,
+// and the headerRecorder of the server
+func newHeaderRecorderCli(t *testing.T, bucket string) (*S3Cli, *headerRecorder) {
+	h := &headerRecorder{headers: map[string]http.Header{}, next: gofakes3.New(s3mem.New()).Server()}
+	ts := httptest.NewServer(h)
+	t.Cleanup(ts.Close)
+	sc := &S3Cli{
+		Endpoint:  ts.URL,
+		AccessKey: "my-ak",
+		SecretKey: "my-sk",
+		Region:    s3.BucketLocationConstraintCnNorth1,
+		PathStyle: true,
+		Writer:    io.Discard,
+	}
+	client, err := NewS3Client(sc)
+	if err != nil {
+		t.Fatal("NewS3Client failed: ", err)
+	}
+	sc.Client = client
+	if _, err := sc.BucketCreate(context.Background(), []string{bucket}); err != nil {
+		t.Fatal("bucketCreate failed: ", err)
+	}
+	return sc, h
+}
+
+func Test_parseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"project=a", "empty=", "k=v=w"})
+	if err != nil {
+		t.Fatal("ParseTags failed: ", err)
+	}
+	if got := encodeTagSet(tags); got != "empty=&k=v%3Dw&project=a" {
+		t.Errorf("unexpected tagging %s", got)
+	}
+	for _, v := range [][]string{{"a"}, {"=v"}, {"a=1", "a=2"}, {strings.Repeat("k", 129) + "=v"}} {
+		if _, err := ParseTags(v); err == nil {
+			t.Errorf("ParseTags(%v) expect error", v)
+		}
+	}
+	many := []string{}
+	for i := 0; i <= maxObjectTags; i++ {
+		many = append(many, string(rune('a'+i))+"=v")
+	}
+	if _, err := ParseTagging(many); err == nil {
+		t.Errorf("ParseTagging %d tags expect error", len(many))
+	}
+}
+
+func Test_taggingDirective(t *testing.T) {
+	cases := []struct {
+		opt    ObjectOptions
+		expect string
+	}{
+		{ObjectOptions{}, s3.TaggingDirectiveCopy},
+		{ObjectOptions{Tagging: "a=1"}, s3.TaggingDirectiveReplace},
+		{ObjectOptions{TaggingDirective: "replace"}, s3.TaggingDirectiveReplace},
+		{ObjectOptions{Tagging: "a=1", TaggingDirective: "COPY"}, ""},
+		{ObjectOptions{TaggingDirective: "MOVE"}, ""},
+	}
+	for _, c := range cases {
+		got, err := c.opt.taggingDirective()
+		if got != c.expect || (err == nil) != (c.expect != "") {
+			t.Errorf("%+v directive expect %q, got %q, %v", c.opt, c.expect, got, err)
+		}
+	}
+}
+
+func Test_objectTaggingHeader(t *testing.T) {
+	sc, h := newHeaderRecorderCli(t, "bucket-tag")
+	ctx := context.Background()
+
+	opt := ObjectOptions{Tagging: "project=a"}
+	if _, err := sc.PutObject(ctx, "bucket-tag", "key", "", nil, false, bytes.NewReader(testObjectContent), opt); err != nil {
+		t.Fatal("putObject failed: ", err)
+	}
+	if got := h.headers[http.MethodPut].Get("X-Amz-Tagging"); got != "project=a" {
+		t.Errorf("putObject x-amz-tagging expect project=a, got %s", got)
+	}
+
+	if _, err := sc.CopyObject(ctx, "bucket-tag/key", "bucket-tag", "key-copy", "", nil, 0, 1, ObjectOptions{}); err != nil {
+		t.Fatal("copyObject failed: ", err)
+	}
+	if got := h.headers[http.MethodPut].Get("X-Amz-Tagging-Directive"); got != "" {
+		t.Errorf("copyObject without tags expect default(COPY) directive, got %s", got)
+	}
+	opt = ObjectOptions{Tagging: "project=b"}
+	if _, err := sc.CopyObject(ctx, "bucket-tag/key", "bucket-tag", "key-copy", "", nil, 0, 1, opt); err != nil {
+		t.Fatal("copyObject failed: ", err)
+	}
+	header := h.headers[http.MethodPut]
+	if header.Get("X-Amz-Tagging-Directive") != s3.TaggingDirectiveReplace || header.Get("X-Amz-Tagging") != "project=b" {
+		t.Errorf("copyObject with tags expect REPLACE directive, got %v", header)
+	}
+}
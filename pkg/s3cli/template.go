@@ -0,0 +1,95 @@
+package s3cli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const OutputTemplate = "template"
+
+// templateFuncs is the helper funcs of output template
+var templateFuncs = template.FuncMap{
+	"human":       humanSize,
+	"rfc3339":     func(v interface{}) string { return formatTime(v, time.RFC3339) },
+	"unix":        unixTime,
+	"timefmt":     func(layout string, v interface{}) string { return formatTime(v, layout) },
+	"urlescape":   func(v interface{}) string { return url.PathEscape(formatValue(v)) },
+	"queryescape": func(v interface{}) string { return url.QueryEscape(formatValue(v)) },
+	"trimquote":   func(v interface{}) string { return strings.Trim(formatValue(v), `"`) },
+}
+
+// parseTemplate parse an output template, escaped \t and \n are tab and newline
+func parseTemplate(text string) (*template.Template, error) {
+	text = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(text)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return template.New("output").Option("missingkey=error").Funcs(templateFuncs).Parse(text)
+}
+
+// toInt64 convert an integer record value to int64
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case *int64:
+		if n != nil {
+			return *n, true
+		}
+	}
+	return 0, false
+}
+
+// humanSize format bytes in binary units(e.g. 1.5MiB)
+func humanSize(v interface{}) string {
+	n, ok := toInt64(v)
+	if !ok {
+		return formatValue(v)
+	}
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// toTime convert a time record value to time.Time
+func toTime(v interface{}) (time.Time, bool) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, !t.IsZero()
+	case *time.Time:
+		if t != nil {
+			return *t, !t.IsZero()
+		}
+	}
+	return time.Time{}, false
+}
+
+// formatTime format a time record value with layout, empty if not a time
+func formatTime(v interface{}, layout string) string {
+	t, ok := toTime(v)
+	if !ok {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// unixTime return the unix timestamp of a time record value
+func unixTime(v interface{}) int64 {
+	t, ok := toTime(v)
+	if !ok {
+		return 0
+	}
+	return t.Unix()
+}
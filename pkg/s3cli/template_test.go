@@ -0,0 +1,91 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_humanSize(t *testing.T) {
+	cases := map[int64]string{
+		0:                  "0B",
+		1023:               "1023B",
+		1024:               "1.0KiB",
+		1536 << 10:         "1.5MiB",
+		5 << 30:            "5.0GiB",
+		int64(3) << 40 / 2: "1.5TiB",
+	}
+	for n, expect := range cases {
+		if got := humanSize(n); got != expect {
+			t.Errorf("humanSize(%d) expect %s, got %s", n, expect, got)
+		}
+	}
+	if got := humanSize(nil); got != "" {
+		t.Errorf("humanSize(nil) expect empty, got %s", got)
+	}
+}
+
+func Test_parseTemplate(t *testing.T) {
+	tmpl, err := parseTemplate(`{{.Key | urlescape}}\t{{.Size | human}}\t{{.LastModified | rfc3339}}\t{{.LastModified | unix}}\t{{timefmt "2006-01-02" .LastModified}}\t{{.ETag | trimquote}}`)
+	if err != nil {
+		t.Fatal("parseTemplate failed: ", err)
+	}
+	buf := &bytes.Buffer{}
+	p := newPrinter(OutputTemplate, objectSchema, buf)
+	p.tmpl = tmpl
+	mtime := time.Date(2022, 5, 1, 8, 0, 0, 0, time.UTC)
+	if err := p.add("STANDARD", mtime, `"etag"`, int64(2048), "owner", "dir/a b"); err != nil {
+		t.Fatal("template add failed: ", err)
+	}
+	expect := "dir%2Fa%20b\t2.0KiB\t2022-05-01T08:00:00Z\t1651392000\t2022-05-01\tetag\n"
+	if buf.String() != expect {
+		t.Errorf("template output expect %q, got %q", expect, buf.String())
+	}
+
+	if _, err := parseTemplate("{{.Key"); err == nil {
+		t.Errorf("invalid template expect error")
+	}
+	tmpl, _ = parseTemplate("{{.NoSuchColumn}}")
+	p = newPrinter(OutputTemplate, objectSchema, buf)
+	p.tmpl = tmpl
+	if err := p.add("STANDARD", mtime, `"etag"`, int64(2048), "owner", "key"); err == nil {
+		t.Errorf("unknown column expect error")
+	}
+}
+
+func Test_templateOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	key := "template-key"
+	if _, err := s3Backend.PutObject(testBucketName, key, nil, bytes.NewReader([]byte("0123456789")), 10); err != nil {
+		t.Fatal("backend PutObject failed: ", err)
+	}
+	sc := s3cliTest
+	sc.Writer = buf
+	sc.Output = OutputSimple
+	if err := sc.SetOutput("", "{{.Key}} {{.Size}}", false); err != nil {
+		t.Fatal("setOutput failed: ", err)
+	}
+	if sc.Output != OutputTemplate {
+		t.Errorf("template expect template output, got %s", sc.Output)
+	}
+	out, err := sc.HeadObject(context.Background(), testBucketName, key)
+	if err := sc.RenderHeadObject(key, HeadOptions{}, out, err); err != nil {
+		t.Fatal("headObject failed: ", err)
+	}
+	if got := strings.TrimSpace(buf.String()); got != key+" 10" {
+		t.Errorf("head template output expect %s 10, got %s", key, got)
+	}
+
+	sc.Output = OutputTemplate
+	sc.tmpl = nil
+	if err := sc.SetOutput("", "", true); err == nil {
+		t.Errorf("template output without template expect error")
+	}
+	sc.Output = OutputCSV
+	if err := sc.SetOutput("", "{{.Key}}", true); err == nil {
+		t.Errorf("template with csv output expect error")
+	}
+}
@@ -0,0 +1,108 @@
+package s3cli
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// TLS env vars(only read if the flag is not set)
+	insecureEnvVar      = "S3_INSECURE"
+	caBundleEnvVar      = "AWS_CA_BUNDLE"
+	clientCertEnvVar    = "S3_CLIENT_CERT"
+	clientKeyEnvVar     = "S3_CLIENT_KEY"
+	tlsServerNameEnvVar = "S3_TLS_SERVER_NAME"
+	tlsMinVersionEnvVar = "S3_TLS_MIN_VERSION"
+)
+
+// tlsVersions is the supported min TLS versions
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// parseTLSVersion parse a TLS version like 1.2(or TLS1.2)
+func parseTLSVersion(v string) (uint16, error) {
+	s := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(v)), "TLS")
+	s = strings.TrimPrefix(s, "V")
+	if version, ok := tlsVersions[s]; ok {
+		return version, nil
+	}
+	return 0, fmt.Errorf("invalid TLS version %s(1.0, 1.1, 1.2 or 1.3)", v)
+}
+
+// tlsEnv fill unset TLS settings from env vars
+func (sc *S3Cli) tlsEnv() error {
+	if !sc.Insecure {
+		if v := os.Getenv(insecureEnvVar); v != "" {
+			insecure, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("invalid %s=%s", insecureEnvVar, v)
+			}
+			sc.Insecure = insecure
+		}
+	}
+	if sc.CABundle == "" {
+		sc.CABundle = os.Getenv(caBundleEnvVar)
+	}
+	if sc.ClientCert == "" {
+		sc.ClientCert = os.Getenv(clientCertEnvVar)
+	}
+	if sc.ClientKey == "" {
+		sc.ClientKey = os.Getenv(clientKeyEnvVar)
+	}
+	if sc.TLSServerName == "" {
+		sc.TLSServerName = os.Getenv(tlsServerNameEnvVar)
+	}
+	if sc.TLSMinVersion == "" {
+		sc.TLSMinVersion = os.Getenv(tlsMinVersionEnvVar)
+	}
+	return nil
+}
+
+// tlsConfig create the TLS config of http client,
+// server certificate is verified(system CAs and CA bundle) unless insecure
+func (sc *S3Cli) tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{
+		InsecureSkipVerify: sc.Insecure,
+		ServerName:         sc.TLSServerName,
+	}
+	if sc.TLSMinVersion != "" {
+		version, err := parseTLSVersion(sc.TLSMinVersion)
+		if err != nil {
+			return nil, err
+		}
+		cfg.MinVersion = version
+	}
+	if sc.CABundle != "" {
+		pem, err := os.ReadFile(sc.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("read CA bundle failed: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in CA bundle %s", sc.CABundle)
+		}
+		cfg.RootCAs = pool
+	}
+	if sc.ClientCert != "" || sc.ClientKey != "" {
+		if sc.ClientCert == "" || sc.ClientKey == "" {
+			return nil, fmt.Errorf("both client certificate and key are required")
+		}
+		cert, err := tls.LoadX509KeyPair(sc.ClientCert, sc.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate failed: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
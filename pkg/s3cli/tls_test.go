@@ -0,0 +1,183 @@
+package s3cli
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+// writeCertFile write certificates as PEM file
+func writeCertFile(t *testing.T, filename string, certs ...*x509.Certificate) {
+	fd, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fd.Close()
+	for _, c := range certs {
+		if err := pem.Encode(fd, &pem.Block{Type: "CERTIFICATE", Bytes: c.Raw}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// newClientCert create a self-signed client certificate and key PEM files
+func newClientCert(t *testing.T, dir string) (*x509.Certificate, string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "s3cli-test-client"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile := filepath.Join(dir, "client.pem")
+	writeCertFile(t, certFile, cert)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyFile := filepath.Join(dir, "client-key.pem")
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return cert, certFile, keyFile
+}
+
+// listBucketsTLS create a S3Cli with TLS settings(modify) and list buckets
+func listBucketsTLS(endpoint string, modify func(sc *S3Cli)) error {
+	sc := &S3Cli{
+		Endpoint:  endpoint,
+		AccessKey: "my-ak",
+		SecretKey: "my-sk",
+		Region:    s3.BucketLocationConstraintCnNorth1,
+		PathStyle: true,
+	}
+	modify(sc)
+	client, err := NewS3Client(sc)
+	if err != nil {
+		return err
+	}
+	_, err = client.ListBucketsWithContext(context.Background(), &s3.ListBucketsInput{})
+	return err
+}
+
+func Test_newS3ClientTLS(t *testing.T) {
+	t.Setenv(caBundleEnvVar, "")
+	ts := httptest.NewTLSServer(gofakes3.New(s3mem.New()).Server())
+	defer ts.Close()
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	writeCertFile(t, caFile, ts.Certificate())
+
+	cases := []struct {
+		name   string
+		modify func(sc *S3Cli)
+		ok     bool
+	}{
+		{"verify by default", func(sc *S3Cli) {}, false},
+		{"insecure", func(sc *S3Cli) { sc.Insecure = true }, true},
+		{"ca-bundle", func(sc *S3Cli) { sc.CABundle = caFile }, true},
+		{"tls-server-name match", func(sc *S3Cli) { sc.CABundle = caFile; sc.TLSServerName = "example.com" }, true},
+		{"tls-server-name mismatch", func(sc *S3Cli) { sc.CABundle = caFile; sc.TLSServerName = "s3.invalid" }, false},
+		{"tls-min-version", func(sc *S3Cli) { sc.CABundle = caFile; sc.TLSMinVersion = "1.3" }, true},
+	}
+	for _, c := range cases {
+		err := listBucketsTLS(ts.URL, c.modify)
+		if c.ok && err != nil {
+			t.Errorf("%s: expect success, got %s", c.name, err)
+		} else if !c.ok && err == nil {
+			t.Errorf("%s: expect error", c.name)
+		}
+	}
+
+	t.Setenv(caBundleEnvVar, caFile)
+	if err := listBucketsTLS(ts.URL, func(sc *S3Cli) {}); err != nil {
+		t.Errorf("ca-bundle from env: expect success, got %s", err)
+	}
+
+	for _, modify := range []func(sc *S3Cli){
+		func(sc *S3Cli) { sc.CABundle = filepath.Join(dir, "not-exist.pem") },
+		func(sc *S3Cli) { sc.TLSMinVersion = "1.4" },
+		func(sc *S3Cli) { sc.ClientCert = caFile },
+	} {
+		sc := &S3Cli{Endpoint: ts.URL}
+		modify(sc)
+		if _, err := NewS3Client(sc); err == nil {
+			t.Errorf("newS3Client invalid TLS settings expect error: %+v", sc)
+		}
+	}
+}
+
+func Test_newS3ClientMTLS(t *testing.T) {
+	t.Setenv(caBundleEnvVar, "")
+	dir := t.TempDir()
+	clientCA, certFile, keyFile := newClientCert(t, dir)
+	pool := x509.NewCertPool()
+	pool.AddCert(clientCA)
+
+	ts := httptest.NewUnstartedServer(gofakes3.New(s3mem.New()).Server())
+	ts.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+	}
+	ts.StartTLS()
+	defer ts.Close()
+	caFile := filepath.Join(dir, "ca.pem")
+	writeCertFile(t, caFile, ts.Certificate())
+
+	if err := listBucketsTLS(ts.URL, func(sc *S3Cli) { sc.CABundle = caFile }); err == nil {
+		t.Errorf("mTLS without client certificate expect error")
+	}
+	if err := listBucketsTLS(ts.URL, func(sc *S3Cli) {
+		sc.CABundle = caFile
+		sc.ClientCert = certFile
+		sc.ClientKey = keyFile
+	}); err != nil {
+		t.Errorf("mTLS with client certificate failed: %s", err)
+	}
+}
+
+func Test_parseTLSVersion(t *testing.T) {
+	cases := map[string]uint16{
+		"1.2":     tls.VersionTLS12,
+		"TLS1.3":  tls.VersionTLS13,
+		"tlsv1.0": tls.VersionTLS10,
+	}
+	for k, v := range cases {
+		if got, err := parseTLSVersion(k); err != nil || got != v {
+			t.Errorf("parseTLSVersion(%s) expect %d, got %d, %v", k, v, got, err)
+		}
+	}
+	if _, err := parseTLSVersion("2.0"); err == nil {
+		t.Errorf("parseTLSVersion(2.0) expect error")
+	}
+}
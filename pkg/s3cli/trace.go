@@ -0,0 +1,215 @@
+package s3cli
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// traceWriter is where request traces are printed(stderr, keep stdout for command output)
+var traceWriter io.Writer = os.Stderr
+
+// traceMu serialize trace output of concurrent requests
+var traceMu sync.Mutex
+
+// requestTrace is the phase timings of a http request,
+// durations are in milliseconds and zero means the phase is skipped(e.g. reused connection)
+type requestTrace struct {
+	Method     string  `json:"method"`
+	URL        string  `json:"url"`
+	Status     int     `json:"status,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	RequestID  string  `json:"requestId,omitempty"` // x-amz-request-id
+	HostID     string  `json:"hostId,omitempty"`    // x-amz-id-2
+	ConnReused bool    `json:"connReused"`
+	DNS        float64 `json:"dns"`      // DNS lookup
+	Connect    float64 `json:"connect"`  // TCP connect
+	TLS        float64 `json:"tls"`      // TLS handshake
+	Send       float64 `json:"send"`     // got connection to request(and body) written
+	TTFB       float64 `json:"ttfb"`     // request written to first response byte
+	Transfer   float64 `json:"transfer"` // first response byte to body read
+	Total      float64 `json:"total"`
+	Bytes      int64   `json:"bytes"` // response body bytes read
+
+	start, dnsStart, connectStart, tlsStart time.Time
+	gotConn, wroteRequest, firstByte        time.Time
+	mu                                      sync.Mutex
+}
+
+// ms return the duration from start to end in milliseconds
+func ms(start, end time.Time) float64 {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0
+	}
+	return float64(end.Sub(start)) / float64(time.Millisecond)
+}
+
+// clientTrace return the httptrace hooks that record phase timestamps
+func (t *requestTrace) clientTrace() *httptrace.ClientTrace {
+	// hooks may be called concurrently(e.g. dial IPv4 and IPv6)
+	record := func(f func(now time.Time)) {
+		now := time.Now()
+		t.mu.Lock()
+		f(now)
+		t.mu.Unlock()
+	}
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			record(func(now time.Time) { t.dnsStart = now })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			record(func(now time.Time) { t.DNS = ms(t.dnsStart, now) })
+		},
+		ConnectStart: func(string, string) {
+			record(func(now time.Time) {
+				if t.connectStart.IsZero() {
+					t.connectStart = now
+				}
+			})
+		},
+		ConnectDone: func(string, string, error) {
+			record(func(now time.Time) { t.Connect = ms(t.connectStart, now) })
+		},
+		TLSHandshakeStart: func() {
+			record(func(now time.Time) { t.tlsStart = now })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			record(func(now time.Time) { t.TLS = ms(t.tlsStart, now) })
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			record(func(now time.Time) {
+				t.gotConn = now
+				t.ConnReused = info.Reused
+			})
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			record(func(now time.Time) { t.wroteRequest = now })
+		},
+		GotFirstResponseByte: func() {
+			record(func(now time.Time) { t.firstByte = now })
+		},
+	}
+}
+
+// finish compute the remaining timings at end
+func (t *requestTrace) finish(end time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.Send = ms(t.gotConn, t.wroteRequest)
+	t.TTFB = ms(t.wroteRequest, t.firstByte)
+	t.Transfer = ms(t.firstByte, end)
+	t.Total = ms(t.start, end)
+}
+
+// String return the trace in line format
+func (t *requestTrace) String() string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, "%s %s", t.Method, t.URL)
+	if t.Status > 0 {
+		fmt.Fprintf(sb, " %d", t.Status)
+	}
+	fmt.Fprintf(sb, " dns=%.3fms connect=%.3fms tls=%.3fms send=%.3fms ttfb=%.3fms transfer=%.3fms total=%.3fms bytes=%d reused=%t",
+		t.DNS, t.Connect, t.TLS, t.Send, t.TTFB, t.Transfer, t.Total, t.Bytes, t.ConnReused)
+	if t.RequestID != "" {
+		fmt.Fprintf(sb, " x-amz-request-id=%s", t.RequestID)
+	}
+	if t.HostID != "" {
+		fmt.Fprintf(sb, " x-amz-id-2=%s", t.HostID)
+	}
+	if t.Error != "" {
+		fmt.Fprintf(sb, " error=%q", t.Error)
+	}
+	return sb.String()
+}
+
+// traceTransport is a http.RoundTripper print phase timings of every request(retries included)
+type traceTransport struct {
+	next http.RoundTripper
+	json bool // print trace as a JSON object per line
+}
+
+// newTraceTransport wrap a http.RoundTripper with request tracing
+func newTraceTransport(next http.RoundTripper, jsonOutput bool) *traceTransport {
+	return &traceTransport{next: next, json: jsonOutput}
+}
+
+// RoundTrip send the request and print its trace when the response body is read or closed
+func (tt *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t := &requestTrace{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		start:  time.Now(),
+	}
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), t.clientTrace()))
+	resp, err := tt.next.RoundTrip(req)
+	if err != nil {
+		t.Error = err.Error()
+		t.finish(time.Now())
+		tt.print(t)
+		return resp, err
+	}
+	t.Status = resp.StatusCode
+	t.RequestID = resp.Header.Get("X-Amz-Request-Id")
+	t.HostID = resp.Header.Get("X-Amz-Id-2")
+	resp.Body = &traceBody{ReadCloser: resp.Body, trace: t, tt: tt}
+	return resp, nil
+}
+
+// print write a trace to traceWriter
+func (tt *traceTransport) print(t *requestTrace) {
+	var line string
+	if tt.json {
+		jo, err := json.Marshal(t)
+		if err != nil {
+			line = t.String()
+		} else {
+			line = string(jo)
+		}
+	} else {
+		line = t.String()
+	}
+	traceMu.Lock()
+	fmt.Fprintln(traceWriter, line)
+	traceMu.Unlock()
+}
+
+// traceBody count the response body and print the trace at EOF or Close
+type traceBody struct {
+	io.ReadCloser
+	trace *requestTrace
+	tt    *traceTransport
+	once  sync.Once
+}
+
+func (b *traceBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.trace.Bytes += int64(n)
+	if err != nil {
+		b.done(err)
+	}
+	return n, err
+}
+
+func (b *traceBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.done(nil)
+	return err
+}
+
+// done print the trace once, an error other than EOF is recorded
+func (b *traceBody) done(err error) {
+	b.once.Do(func() {
+		if err != nil && err != io.EOF {
+			b.trace.Error = err.Error()
+		}
+		b.trace.finish(time.Now())
+		b.tt.print(b.trace)
+	})
+}
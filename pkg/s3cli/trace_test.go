@@ -0,0 +1,86 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+)
+
+func Test_traceTransport(t *testing.T) {
+	ts := httptest.NewServer(gofakes3.New(s3mem.New()).Server())
+	defer ts.Close()
+	buf := &bytes.Buffer{}
+	defer func(w io.Writer) { traceWriter = w }(traceWriter)
+	traceWriter = buf
+
+	for _, output := range []string{OutputLine, OutputJSON} {
+		buf.Reset()
+		sc := &S3Cli{
+			Endpoint:  ts.URL,
+			AccessKey: "my-ak",
+			SecretKey: "my-sk",
+			Region:    s3.BucketLocationConstraintCnNorth1,
+			PathStyle: true,
+			Output:    output,
+			Trace:     true,
+		}
+		client, err := NewS3Client(sc)
+		if err != nil {
+			t.Fatal("newS3Client failed: ", err)
+		}
+		ctx := context.Background()
+		bucket := "trace-" + output
+		if _, err := client.CreateBucketWithContext(ctx, &s3.CreateBucketInput{Bucket: aws.String(bucket)}); err != nil {
+			t.Fatal("create bucket failed: ", err)
+		}
+		if _, err := client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(testObjectKey),
+			Body:   bytes.NewReader(testObjectContent),
+		}); err != nil {
+			t.Fatal("put object failed: ", err)
+		}
+		obj, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+			Bucket: aws.String(bucket),
+			Key:    aws.String(testObjectKey),
+		})
+		if err != nil {
+			t.Fatal("get object failed: ", err)
+		}
+		io.Copy(io.Discard, obj.Body)
+		obj.Body.Close()
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if len(lines) != 3 {
+			t.Fatalf("%s output expect 3 traces, got %d: %s", output, len(lines), buf.String())
+		}
+		last := lines[2]
+		if output == OutputJSON {
+			rt := &requestTrace{}
+			if err := json.Unmarshal([]byte(last), rt); err != nil {
+				t.Fatalf("invalid json trace %s: %s", last, err)
+			}
+			if rt.Method != "GET" || rt.Status != 200 || rt.RequestID == "" || rt.HostID == "" {
+				t.Errorf("unexpected json trace %s", last)
+			}
+			if rt.Bytes != int64(len(testObjectContent)) || rt.Total <= 0 {
+				t.Errorf("json trace expect %d bytes and total time: %s", len(testObjectContent), last)
+			}
+		} else {
+			for _, v := range []string{"GET ", " 200 ", "ttfb=", "transfer=", "x-amz-request-id=", "x-amz-id-2="} {
+				if !strings.Contains(last, v) {
+					t.Errorf("line trace expect %q: %s", v, last)
+				}
+			}
+		}
+	}
+}
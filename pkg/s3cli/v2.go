@@ -1,4 +1,4 @@
-package main
+package s3cli
 
 import (
 	"crypto/hmac"
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"fmt"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,18 +16,25 @@ import (
 // URL parameters that need to be added to the signature
 var s3ParamsToSign = map[string]struct{}{
 	"acl":                          {},
+	"lifecycle":                    {},
+	"legal-hold":                   {},
 	"location":                     {},
 	"logging":                      {},
 	"notification":                 {},
+	"object-lock":                  {},
 	"partNumber":                   {},
+	"replication":                  {},
 	"policy":                       {},
 	"requestPayment":               {},
+	"retention":                    {},
 	"torrent":                      {},
+	"tagging":                      {},
 	"uploadId":                     {},
 	"uploads":                      {},
 	"versionId":                    {},
 	"versioning":                   {},
 	"versions":                     {},
+	"website":                      {},
 	"response-content-type":        {},
 	"response-content-language":    {},
 	"response-expires":             {},
@@ -35,8 +43,8 @@ var s3ParamsToSign = map[string]struct{}{
 	"response-content-encoding":    {},
 }
 
-// presignV2 presigne URL with escaped key(Object name).
-func v2Presign(AccessKey, SecretKey string, expireTime time.Duration, req *http.Request) {
+// PresignV2 presigne URL of req with escaped key(Object name).
+func PresignV2(AccessKey, SecretKey string, expireTime time.Duration, req *http.Request) {
 	exp := strconv.FormatInt(time.Now().Unix()+int64(expireTime.Seconds()), 10)
 
 	q := req.URL.Query()
@@ -54,20 +62,14 @@ func v2Presign(AccessKey, SecretKey string, expireTime time.Duration, req *http.
 	req.URL.RawQuery = q.Encode()
 }
 
-// sign signs requests using v2 auth
+// SignV2 signs requests using v2 auth
 //
 // Cobbled together from goamz and aws-sdk-go
-func sign(AccessKey, SecretKey string, req *http.Request) {
+func SignV2(AccessKey, SecretKey string, req *http.Request) {
 	// Set date
 	date := time.Now().UTC().Format(time.RFC1123)
 	req.Header.Set("Date", date)
 
-	// Sort out URI
-	uri := req.URL.EscapedPath()
-	if uri == "" {
-		uri = "/"
-	}
-
 	// Look through headers of interest
 	var md5 string
 	var contentType string
@@ -103,10 +105,26 @@ func sign(AccessKey, SecretKey string, req *http.Request) {
 		joinedHeadersToSign = strings.Join(headersToSign, "\n") + "\n"
 	}
 
-	// Look for query parameters which need to be added to the signature
-	params := req.URL.Query()
+	// Make signature
+	payload := req.Method + "\n" + md5 + "\n" + contentType + "\n" + date + "\n" + joinedHeadersToSign + canonicalResourceV2(req.URL)
+	hash := hmac.New(sha1.New, []byte(SecretKey))
+	_, _ = hash.Write([]byte(payload))
+	signature := make([]byte, base64.StdEncoding.EncodedLen(hash.Size()))
+	base64.StdEncoding.Encode(signature, hash.Sum(nil))
+
+	// Set signature in request
+	req.Header.Set("Authorization", "AWS "+AccessKey+":"+string(signature))
+}
+
+// canonicalResourceV2 return the v2 canonical resource of u,
+// the escaped path with the sorted subresource(s3ParamsToSign) query parameters
+func canonicalResourceV2(u *url.URL) string {
+	uri := u.EscapedPath()
+	if uri == "" {
+		uri = "/"
+	}
 	var queriesToSign []string
-	for k, vs := range params {
+	for k, vs := range u.Query() {
 		if _, ok := s3ParamsToSign[k]; ok {
 			for _, v := range vs {
 				if v == "" {
@@ -117,19 +135,29 @@ func sign(AccessKey, SecretKey string, req *http.Request) {
 			}
 		}
 	}
-	// Add query parameters to URI
 	if len(queriesToSign) > 0 {
-		sort.StringSlice(queriesToSign).Sort()
+		sort.Strings(queriesToSign)
 		uri += "?" + strings.Join(queriesToSign, "&")
 	}
+	return uri
+}
 
-	// Make signature
-	payload := req.Method + "\n" + md5 + "\n" + contentType + "\n" + date + "\n" + joinedHeadersToSign + uri
-	hash := hmac.New(sha1.New, []byte(SecretKey))
-	_, _ = hash.Write([]byte(payload))
-	signature := make([]byte, base64.StdEncoding.EncodedLen(hash.Size()))
-	base64.StdEncoding.Encode(signature, hash.Sum(nil))
+// V2Signer is a http.RoundTripper that signs requests using v2 auth
+// before sending them with Transport(default http.DefaultTransport),
+// it lets plain http clients talk to S3 services that only support v2
+type V2Signer struct {
+	AccessKey string
+	SecretKey string
+	Transport http.RoundTripper
+}
 
-	// Set signature in request
-	req.Header.Set("Authorization", "AWS "+AccessKey+":"+string(signature))
+// RoundTrip signs a copy of req and sends it
+func (s *V2Signer) RoundTrip(req *http.Request) (*http.Response, error) {
+	tp := s.Transport
+	if tp == nil {
+		tp = http.DefaultTransport
+	}
+	r := req.Clone(req.Context())
+	SignV2(s.AccessKey, s.SecretKey, r)
+	return tp.RoundTrip(r)
 }
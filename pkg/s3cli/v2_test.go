@@ -0,0 +1,64 @@
+package s3cli
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func Test_V2Signer(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload := r.Method + "\n\n\n" + r.Header.Get("Date") + "\nx-amz-meta-a:b\n" + r.URL.EscapedPath() + "?acl"
+		mac := hmac.New(sha1.New, []byte("sk"))
+		mac.Write([]byte(payload))
+		expect := "AWS ak:" + base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		if got := r.Header.Get("Authorization"); got != expect {
+			t.Errorf("Authorization expect: %s, got: %s", expect, got)
+		}
+	}))
+	defer ts.Close()
+
+	client := &http.Client{Transport: &V2Signer{AccessKey: "ak", SecretKey: "sk"}}
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/bucket/key?acl", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Amz-Meta-A", "b")
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal("signed request failed: ", err)
+	}
+	resp.Body.Close()
+	if req.Header.Get("Authorization") != "" {
+		t.Errorf("V2Signer should not modify the original request")
+	}
+}
+
+func Test_canonicalResourceV2(t *testing.T) {
+	cases := map[string]string{
+		"/bucket/key?acl":                     "/bucket/key?acl",
+		"/bucket/key?versionId=v1&foo=1":      "/bucket/key?versionId=v1",
+		"/bucket/key?uploadId=u&partNumber=2": "/bucket/key?partNumber=2&uploadId=u",
+		"/bucket?lifecycle":                   "/bucket?lifecycle",
+		"/bucket?website":                     "/bucket?website",
+		"/bucket?replication":                 "/bucket?replication",
+		"/bucket/key?retention&versionId=v1":  "/bucket/key?retention&versionId=v1",
+		"/bucket/key?legal-hold":              "/bucket/key?legal-hold",
+		"/bucket?object-lock":                 "/bucket?object-lock",
+		"/bucket/key?tagging&versionId=v1":    "/bucket/key?tagging&versionId=v1",
+		"?list-type=2":                        "/",
+	}
+	for raw, expect := range cases {
+		u, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := canonicalResourceV2(u); got != expect {
+			t.Errorf("%s canonical resource expect %s, got %s", raw, expect, got)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package s3cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+var websiteSchema = schema{
+	columns: []string{"IndexDocument", "ErrorDocument", "RedirectAll", "RoutingRules"},
+	simple:  []string{"IndexDocument", "ErrorDocument", "RedirectAll"},
+}
+
+// WebsiteOptions is a Bucket website configuration of command flags,
+// RedirectAll(host or protocol://host) is exclusive with the others
+type WebsiteOptions struct {
+	IndexDocument    string // index document suffix, e.g. index.html
+	ErrorDocument    string // error document key, e.g. 404.html
+	RedirectAll      string // redirect all requests to host or protocol://host
+	RoutingRulesFile string // routing rules(JSON or YAML list) file
+}
+
+// Config create the website configuration of o
+func (o WebsiteOptions) Config() (*s3.WebsiteConfiguration, error) {
+	cfg := &s3.WebsiteConfiguration{}
+	if o.RedirectAll != "" {
+		r := &s3.RedirectAllRequestsTo{HostName: aws.String(o.RedirectAll)}
+		if n := strings.Index(o.RedirectAll, "://"); n >= 0 {
+			r.Protocol = aws.String(o.RedirectAll[:n])
+			r.HostName = aws.String(o.RedirectAll[n+3:])
+		}
+		cfg.RedirectAllRequestsTo = r
+	}
+	if o.IndexDocument != "" {
+		cfg.IndexDocument = &s3.IndexDocument{Suffix: aws.String(o.IndexDocument)}
+	}
+	if o.ErrorDocument != "" {
+		cfg.ErrorDocument = &s3.ErrorDocument{Key: aws.String(o.ErrorDocument)}
+	}
+	if o.RoutingRulesFile != "" {
+		rules, err := LoadRoutingRules(o.RoutingRulesFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RoutingRules = rules
+	}
+	return cfg, nil
+}
+
+// ObjectWebsiteOptions is the website settings of a new Object
+type ObjectWebsiteOptions struct {
+	WebsiteRedirect string // x-amz-website-redirect-location, an Object key(/path) or URL
+}
+
+// validate validate the website redirect of o
+func (o ObjectWebsiteOptions) validate() error {
+	if r := o.WebsiteRedirect; r != "" && !strings.HasPrefix(r, "/") &&
+		!strings.HasPrefix(r, "http://") && !strings.HasPrefix(r, "https://") {
+		return fmt.Errorf("invalid website redirect %s(start with /, http:// or https://)", r)
+	}
+	return nil
+}
+
+// websiteRedirect return the website redirect location of o, nil if not set
+func (o ObjectWebsiteOptions) websiteRedirect() *string {
+	if o.WebsiteRedirect == "" {
+		return nil
+	}
+	return aws.String(o.WebsiteRedirect)
+}
+
+// LoadRoutingRules load the website routing rules(JSON or YAML list) of file
+func LoadRoutingRules(filename string) ([]*s3.RoutingRule, error) {
+	rules := []*s3.RoutingRule{}
+	if err := decodeFile(filename, &rules); err != nil {
+		return nil, fmt.Errorf("load routing rules %s failed: %w", filename, err)
+	}
+	return rules, nil
+}
+
+// validProtocol check the protocol of a website redirect
+func validProtocol(protocol *string) error {
+	switch aws.StringValue(protocol) {
+	case "", s3.ProtocolHttp, s3.ProtocolHttps:
+		return nil
+	}
+	return fmt.Errorf("invalid redirect protocol %q(http or https)", aws.StringValue(protocol))
+}
+
+// validateWebsite check a website configuration before put it
+func validateWebsite(cfg *s3.WebsiteConfiguration) error {
+	if cfg == nil {
+		return errors.New("no website configuration")
+	}
+	if r := cfg.RedirectAllRequestsTo; r != nil {
+		if cfg.IndexDocument != nil || cfg.ErrorDocument != nil || len(cfg.RoutingRules) > 0 {
+			return errors.New("redirect all requests is exclusive with index/error document and routing rules")
+		}
+		if aws.StringValue(r.HostName) == "" {
+			return errors.New("redirect all requests requires a host name")
+		}
+		if err := validProtocol(r.Protocol); err != nil {
+			return err
+		}
+		return cfg.Validate()
+	}
+	if cfg.IndexDocument == nil {
+		return errors.New("website requires an index document(or redirect all requests)")
+	}
+	if suffix := aws.StringValue(cfg.IndexDocument.Suffix); suffix == "" || strings.Contains(suffix, "/") {
+		return fmt.Errorf("invalid index document %q(not empty and no /)", suffix)
+	}
+	for i, r := range cfg.RoutingRules {
+		if r == nil || r.Redirect == nil {
+			return fmt.Errorf("routing rule #%d: no Redirect", i+1)
+		}
+		d := r.Redirect
+		if d.ReplaceKeyPrefixWith != nil && d.ReplaceKeyWith != nil {
+			return fmt.Errorf("routing rule #%d: ReplaceKeyPrefixWith and ReplaceKeyWith are exclusive", i+1)
+		}
+		if err := validProtocol(d.Protocol); err != nil {
+			return fmt.Errorf("routing rule #%d: %w", i+1, err)
+		}
+		if c := aws.StringValue(d.HttpRedirectCode); c != "" && (len(c) != 3 || c[0] != '3') {
+			return fmt.Errorf("routing rule #%d: invalid HttpRedirectCode %s(3XX)", i+1, c)
+		}
+		if c := r.Condition; c != nil {
+			if c.HttpErrorCodeReturnedEquals == nil && c.KeyPrefixEquals == nil {
+				return fmt.Errorf("routing rule #%d: empty Condition", i+1)
+			}
+			if v := aws.StringValue(c.HttpErrorCodeReturnedEquals); v != "" && (len(v) != 3 || v[0] != '4' && v[0] != '5') {
+				return fmt.Errorf("routing rule #%d: invalid HttpErrorCodeReturnedEquals %s(4XX or 5XX)", i+1, v)
+			}
+		}
+	}
+	return cfg.Validate()
+}
+
+// BucketWebsiteGet get a Bucket's website configuration
+func (sc *S3Cli) BucketWebsiteGet(ctx context.Context, bucket string) (*s3.GetBucketWebsiteOutput, error) {
+	req, resp := sc.Client.GetBucketWebsiteRequest(&s3.GetBucketWebsiteInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return nil, err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return nil, fmt.Errorf("get website failed: %w", err)
+	}
+	return resp, nil
+}
+
+// BucketWebsitePut validate and put(replace) a Bucket's website configuration
+func (sc *S3Cli) BucketWebsitePut(ctx context.Context, bucket string, cfg *s3.WebsiteConfiguration) error {
+	if err := validateWebsite(cfg); err != nil {
+		return err
+	}
+	req, resp := sc.Client.PutBucketWebsiteRequest(&s3.PutBucketWebsiteInput{
+		Bucket:               aws.String(bucket),
+		WebsiteConfiguration: cfg,
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("put website failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	} else if sc.jsonOutput() {
+		if err := sc.printJSON(cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BucketWebsiteDelete delete a Bucket's website configuration
+func (sc *S3Cli) BucketWebsiteDelete(ctx context.Context, bucket string) error {
+	req, resp := sc.Client.DeleteBucketWebsiteRequest(&s3.DeleteBucketWebsiteInput{
+		Bucket: aws.String(bucket),
+	})
+	req.SetContext(ctx)
+	if sc.Presign {
+		s, err := req.Presign(sc.PresignExp)
+		if err == nil {
+			fmt.Fprintln(sc.out(), s)
+		}
+		return err
+	}
+
+	sc.addCustomHeader(req.HTTPRequest)
+	err := req.Send()
+	if err != nil {
+		return fmt.Errorf("delete website failed: %w", err)
+	}
+	if sc.verboseOutput() {
+		fmt.Fprintln(sc.out(), resp)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package s3cli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+func Test_websiteOptions(t *testing.T) {
+	cfg, err := WebsiteOptions{RedirectAll: "https://example.com"}.Config()
+	if err != nil {
+		t.Fatal("Config failed: ", err)
+	}
+	if err := validateWebsite(cfg); err != nil {
+		t.Errorf("redirect all expect valid, got %s", err)
+	}
+	if r := cfg.RedirectAllRequestsTo; aws.StringValue(r.Protocol) != s3.ProtocolHttps || aws.StringValue(r.HostName) != "example.com" {
+		t.Errorf("unexpected redirect all %v", r)
+	}
+
+	filename := filepath.Join(t.TempDir(), "rules.yaml")
+	rules := `
+- condition:
+    keyPrefixEquals: docs/
+  redirect:
+    replaceKeyPrefixWith: documents/
+- condition:
+    httpErrorCodeReturnedEquals: "404"
+  redirect:
+    hostName: example.com
+    httpRedirectCode: "302"
+`
+	if err := os.WriteFile(filename, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = WebsiteOptions{IndexDocument: "index.html", ErrorDocument: "404.html", RoutingRulesFile: filename}.Config()
+	if err != nil {
+		t.Fatal("Config failed: ", err)
+	}
+	if err := validateWebsite(cfg); err != nil {
+		t.Errorf("index and routing rules expect valid, got %s", err)
+	}
+	if len(cfg.RoutingRules) != 2 || aws.StringValue(cfg.RoutingRules[1].Redirect.HttpRedirectCode) != "302" {
+		t.Errorf("unexpected routing rules %v", cfg.RoutingRules)
+	}
+
+	if err := os.WriteFile(filename, []byte("- redirect:\n    host: example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (WebsiteOptions{IndexDocument: "index.html", RoutingRulesFile: filename}).Config(); err == nil {
+		t.Errorf("unknown field expect error")
+	}
+}
+
+func Test_validateWebsite(t *testing.T) {
+	index := &s3.IndexDocument{Suffix: aws.String("index.html")}
+	rule := func(r *s3.Redirect) []*s3.RoutingRule {
+		return []*s3.RoutingRule{{Redirect: r}}
+	}
+	cases := map[string]*s3.WebsiteConfiguration{
+		"empty":          {},
+		"invalid index":  {IndexDocument: &s3.IndexDocument{Suffix: aws.String("a/index.html")}},
+		"redirect index": {IndexDocument: index, RedirectAllRequestsTo: &s3.RedirectAllRequestsTo{HostName: aws.String("example.com")}},
+		"redirect protocol": {RedirectAllRequestsTo: &s3.RedirectAllRequestsTo{
+			HostName: aws.String("example.com"),
+			Protocol: aws.String("ftp"),
+		}},
+		"no redirect":   {IndexDocument: index, RoutingRules: []*s3.RoutingRule{{}}},
+		"redirect code": {IndexDocument: index, RoutingRules: rule(&s3.Redirect{HttpRedirectCode: aws.String("404")})},
+		"replace key and prefix": {IndexDocument: index, RoutingRules: rule(&s3.Redirect{
+			ReplaceKeyWith:       aws.String("a"),
+			ReplaceKeyPrefixWith: aws.String("b/"),
+		})},
+		"error code": {IndexDocument: index, RoutingRules: []*s3.RoutingRule{{
+			Condition: &s3.Condition{HttpErrorCodeReturnedEquals: aws.String("200")},
+			Redirect:  &s3.Redirect{HostName: aws.String("example.com")},
+		}}},
+	}
+	for name, cfg := range cases {
+		if err := validateWebsite(cfg); err == nil {
+			t.Errorf("%s expect error", name)
+		}
+	}
+}
+
+func Test_websiteRedirect(t *testing.T) {
+	if err := (ObjectWebsiteOptions{WebsiteRedirect: "new.html"}).validate(); err == nil {
+		t.Errorf("website redirect without / or URL expect error")
+	}
+	sc, h := newHeaderRecorderCli(t, "bucket-website")
+	ctx := context.Background()
+	opt := ObjectOptions{ObjectWebsiteOptions: ObjectWebsiteOptions{WebsiteRedirect: "/new.html"}}
+	if _, err := sc.PutObject(ctx, "bucket-website", "old.html", "", nil, false, bytes.NewReader(nil), opt); err != nil {
+		t.Fatal("putObject failed: ", err)
+	}
+	if got := h.headers[http.MethodPut].Get("X-Amz-Website-Redirect-Location"); got != "/new.html" {
+		t.Errorf("putObject x-amz-website-redirect-location expect /new.html, got %q", got)
+	}
+}